@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/marhaupe/json2struct/pkg/config"
+	"github.com/marhaupe/json2struct/pkg/generator"
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"github.com/spf13/pflag"
+)
+
+// loadConfig loads the config file discovered from the working directory. It returns nil if there's none.
+func loadConfig() (*config.Config, error) {
+	path, err := config.Find(".")
+	if errors.Is(err, config.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// applyConfig sets the flags that weren't given on the command line to the defaults of the config file, if
+// there is one.
+func applyConfig(flags *pflag.FlagSet) error {
+	cfg, err := loadConfig()
+	if err != nil || cfg == nil {
+		return err
+	}
+	defaults := cfg.Defaults
+	values := map[string][]string{
+		"tag":      defaults.TagFlags(),
+		"override": defaults.OverrideFlags(),
+	}
+	if defaults.Package != "" {
+		values["package"] = []string{defaults.Package}
+	}
+	if defaults.FieldOrder != "" {
+		values["field-order"] = []string{defaults.FieldOrder}
+	}
+	for name, flagValues := range values {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		for _, value := range flagValues {
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("%v: %v", cfg.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// runGenerateAll runs every job of the config file and reports the outcome of each.
+func runGenerateAll() {
+	if hasInput() {
+		fmt.Println("--all reads the inputs of the jobs in " + config.FileName + " and can't be combined with --string, --file, --url or --clipboard")
+		os.Exit(1)
+	}
+	cfg, err := loadConfig()
+	if err == nil && cfg == nil {
+		err = config.ErrNotFound
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	errs := runJobs(cfg)
+	failed := 0
+	for i, job := range cfg.Jobs {
+		if errs[i] != nil {
+			fmt.Printf("%v: %v\n", job.Input, errs[i])
+			failed++
+			continue
+		}
+		fmt.Printf("%v -> %v\n", job.Input, job.Output)
+	}
+	if failed > 0 {
+		fmt.Printf("%d of %d jobs failed\n", failed, len(cfg.Jobs))
+		os.Exit(1)
+	}
+}
+
+// runJobs runs the jobs of cfg in parallel and returns their errors in the order of the jobs.
+func runJobs(cfg *config.Config) []error {
+	errs := make([]error, len(cfg.Jobs))
+	limit := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, job := range cfg.Jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			errs[i] = runJob(cfg, job)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// runJob generates the Go types for the input of job and writes them to its output.
+func runJob(cfg *config.Config, job config.Job) error {
+	opts, err := cfg.Options(job)
+	if err != nil {
+		return err
+	}
+	format := job.InputFormat
+	if format == "" {
+		format = formatOfFile(job.Input)
+	}
+	data, err := os.ReadFile(cfg.Resolve(job.Input))
+	if err != nil {
+		return err
+	}
+
+	parseOpts := parse.Options{
+		Warn: func(msg string) {
+			fmt.Fprintf(os.Stderr, "warning: %v: %v\n", job.Input, msg)
+		},
+	}
+	selector, err := parse.ParsePath(job.Path)
+	if err != nil {
+		return err
+	}
+	opts.Format = generator.FormatGo
+	result, err := generateFrom(string(data), format, selector, parseOpts, opts)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %v: %v\n", job.Input, warning)
+	}
+
+	output := cfg.Resolve(job.Output)
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(output, []byte(result.Code), 0o644)
+}
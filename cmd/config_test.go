@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/config"
+	"github.com/spf13/pflag"
+)
+
+func TestRunJobs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		config.FileName: `
+defaults:
+  package: api
+  overrides:
+    created_at: time.Time
+jobs:
+  - input: samples/user.json
+    output: api/user.go
+    type: User
+  - input: samples/config.yml
+    output: api/config.go
+    type: Config
+  - input: samples/invalid.json
+    output: api/invalid.go
+  - input: samples/page.json
+    output: api/item.go
+    path: data.items[*]
+  - input: samples/user.schema.json
+    output: api/schema.go
+    input_format: jsonschema
+`,
+		"samples/user.json":    `{"id": 1, "created_at": "2024-01-02T03:04:05Z"}`,
+		"samples/config.yml":   "level: debug\n",
+		"samples/invalid.json": `{"id": 1,}`,
+		"samples/page.json":    `{"data": {"items": [{"sku": "a"}, {"sku": "b", "count": 2}]}}`,
+		// The overrides of the defaults can't be applied to the types of a schema.
+		"samples/user.schema.json": `{"properties": {"created_at": {"type": "string"}}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.Load(filepath.Join(dir, config.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := runJobs(cfg)
+	if errs[0] != nil || errs[1] != nil || errs[2] == nil || errs[3] != nil || errs[4] == nil {
+		t.Fatalf("runJobs(): got %v, want an error for the invalid input and the schema only", errs)
+	}
+
+	want := map[string][]string{
+		"api/user.go":   {"package api", "type User struct", "Created_at time.Time `json:\"created_at\"`"},
+		"api/config.go": {"type Config struct", "`json:\"level\" yaml:\"level\"`"},
+		"api/item.go":   {"type Item struct", "Sku   string `json:\"sku\"`"},
+	}
+	for name, substrings := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, substring := range substrings {
+			if !strings.Contains(string(data), substring) {
+				t.Errorf("%v: %q is missing in\n%s", name, substring, data)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api/invalid.go")); !os.IsNotExist(err) {
+		t.Errorf("runJobs(): the output of the failed job exists")
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	dir := t.TempDir()
+	content := "defaults:\n  package: api\n  field_order: source\n  naming: snake\n  tags: [db]\n"
+	if err := os.WriteFile(filepath.Join(dir, config.FileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	defer func(p, o string, t []string) {
+		packageName, fieldOrder, tags = p, o, t
+	}(packageName, fieldOrder, tags)
+
+	flags := pflag.NewFlagSet("generate", pflag.ContinueOnError)
+	addTypeFlags(flags)
+	addGoFlags(flags)
+	if err := flags.Parse([]string{"--package", "cli"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(flags); err != nil {
+		t.Fatal(err)
+	}
+	if packageName != "cli" || fieldOrder != "source" || !reflect.DeepEqual(tags, []string{"db:snake"}) {
+		t.Errorf("applyConfig(): got --package %v, --field-order %v and --tag %v", packageName, fieldOrder, tags)
+	}
+}
+
+func TestParseTypeOverrides(t *testing.T) {
+	got, err := parseTypeOverrides([]string{"id=string", "a=b=time.Time"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"id": "string", "a=b": "time.Time"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTypeOverrides(): got %v, want %v", got, want)
+	}
+	if _, err := parseTypeOverrides([]string{"id"}); err == nil {
+		t.Errorf("parseTypeOverrides(): expected an error for a value without =")
+	}
+}
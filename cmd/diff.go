@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"github.com/marhaupe/json2struct/pkg/verify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffType    string
+	diffSamples []string
+
+	diffCmd = &cobra.Command{
+		Use:   "diff --type pkg.Type --sample sample.json",
+		Short: "compare an existing Go type to JSON samples",
+		Long: "diff loads an existing Go type and compares it to the shape inferred from the samples. It reports " +
+			"keys without a field, fields whose key isn't in any sample, values that don't fit their field and " +
+			"keys that only match their json tag case-insensitively, and exits with status 1 if there are any.",
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			differences, err := runDiff(diffType, diffSamples)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(2)
+			}
+			for _, difference := range differences {
+				fmt.Println(difference)
+			}
+			if len(differences) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	diffCmd.Flags().StringVar(&diffType, "type", "", "the Go type to compare, e.g. ./api.User or github.com/example/api.User")
+	diffCmd.Flags().StringArrayVar(&diffSamples, "sample", nil, "path to a JSON sample. can be repeated")
+	addDuplicateKeysFlag(diffCmd.Flags())
+	rootCmd.AddCommand(diffCmd)
+}
+
+// runDiff compares the type named by qualifiedName to the samples in files.
+func runDiff(qualifiedName string, files []string) ([]verify.Difference, error) {
+	if qualifiedName == "" || len(files) == 0 {
+		return nil, errors.New("diff needs --type and at least one --sample")
+	}
+	policy, err := parse.ParseDuplicateKeyPolicy(duplicateKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []parse.Node
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		// Integers are checked for overflows, which needs their values.
+		sample, err := parse.ParseFromStringWithOptions(string(data), parse.Options{DuplicateKeys: policy, KeepValues: true})
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file, err)
+		}
+		samples = append(samples, sample)
+	}
+
+	typ, err := verify.LoadType(qualifiedName, "")
+	if err != nil {
+		return nil, err
+	}
+	return verify.Diff(typ, model.Infer(samples...)), nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunDiff(t *testing.T) {
+	sample := filepath.Join(t.TempDir(), "user.json")
+	content := `{ "id": 1, "Name": "Ada", "age": 36, "created_at": "2024-03-01T12:30:00Z", "legacy_id": "a",
+		"address": null, "tags": [], "nickname": "ada" }`
+	if err := os.WriteFile(sample, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	differences, err := runDiff("../pkg/verify/testdata/api.User", []string{sample})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(differences) != 1 || differences[0].String() != "missing field: nickname: no field for the key, whose values are string" {
+		t.Errorf("runDiff(): got %v, want only the missing nickname", differences)
+	}
+
+	if _, err := runDiff("", []string{sample}); err == nil {
+		t.Errorf("runDiff(): expected an error without --type")
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/generator"
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"github.com/spf13/cobra"
+)
+
+var (
+	payloadKey   string
+	envelopeName string
+
+	envelopeCmd = &cobra.Command{
+		Use:   "envelope sample.json...",
+		Short: "generate a generic type for the envelope shared by JSON samples and a type per payload",
+		Long: "envelope generates a generic Go type for the envelope shared by the responses of several endpoints, " +
+			"e.g. Envelope[T any] for {status, error, data}, a type for the payload of every sample named after its " +
+			"file, and an alias for the envelope holding it, e.g. User and UserEnvelope for user.json. Samples of " +
+			"the same name in different directories are merged. Without --key, the payload is the key whose shape " +
+			"differs between the samples.",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := runEnvelope(args)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if outputFile != "" {
+				if err := os.WriteFile(outputFile, []byte(result.Code), 0o644); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
+			}
+			fmt.Println(result.Code)
+		},
+	}
+)
+
+func init() {
+	envelopeCmd.Flags().StringVar(&payloadKey, "key", "", "key of the payload within the envelope (default: detected from the samples)")
+	envelopeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "write the generated code to this file instead of stdout")
+	addDuplicateKeysFlag(envelopeCmd.Flags())
+	envelopeCmd.Flags().StringVar(&envelopeName, "type", "Envelope", "name of the generic envelope type")
+	addFieldOrderFlag(envelopeCmd.Flags())
+	addGoFlags(envelopeCmd.Flags())
+	rootCmd.AddCommand(envelopeCmd)
+}
+
+// runEnvelope generates the envelope and payload types for the samples in files, which may be JSON, YAML or TOML
+// according to their extension.
+func runEnvelope(files []string) (*generator.Result, error) {
+	policy, err := parse.ParseDuplicateKeyPolicy(duplicateKeys)
+	if err != nil {
+		return nil, err
+	}
+	generatorOptions, err := makeGeneratorOptions()
+	if err != nil {
+		return nil, err
+	}
+	generatorOptions.TypeName = envelopeName
+	generatorOptions.Format = generator.FormatGo
+
+	var samples []generator.EnvelopeSample
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		sample, err := parseSample(string(data), formatOfFile(file), parse.Options{DuplicateKeys: policy})
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		samples = append(samples, generator.EnvelopeSample{Name: name, Tree: sample})
+	}
+	return generator.GenerateEnvelope(samples, payloadKey, generatorOptions)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunEnvelope(t *testing.T) {
+	dir := t.TempDir()
+	samples := map[string]string{
+		"user.json":        `{"status": "ok", "data": {"id": 1}}`,
+		"list-orders.yaml": "status: ok\ndata:\n  - total: 2.5\n",
+	}
+	var files []string
+	for _, name := range []string{"user.json", "list-orders.yaml"} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(samples[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	result, err := runEnvelope(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type Envelope[T any] struct",
+		"type User struct",
+		"type UserEnvelope = Envelope[User]",
+		"type ListOrders []struct",
+		"type ListOrdersEnvelope = Envelope[ListOrders]",
+	} {
+		if !strings.Contains(result.Code, want) {
+			t.Errorf("runEnvelope(): expected %q in\n%v", want, result.Code)
+		}
+	}
+
+	if _, err := runEnvelope([]string{filepath.Join(dir, "missing.json")}); err == nil {
+		t.Errorf("runEnvelope(): expected an error for a missing file")
+	}
+}
+
+func TestEnvelopeTypeFlag(t *testing.T) {
+	flag := envelopeCmd.Flags().Lookup("type")
+	if flag.DefValue != "Envelope" || strings.Contains(flag.Usage, "JSONToStruct") {
+		t.Errorf("envelope --type: got default %q and usage %q", flag.DefValue, flag.Usage)
+	}
+	if typeName != "" {
+		t.Errorf("envelope --type changed the default of the root type name to %q", typeName)
+	}
+}
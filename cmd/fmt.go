@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"github.com/spf13/cobra"
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "check and indent a JSON sample",
+	Long: "fmt checks that the input is valid JSON and prints it indented, keeping the order of the keys. " +
+		"Without --string, --file, --url or --clipboard, it reads the input from stdin.",
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		var userInput string
+		var err error
+		if hasInput() {
+			userInput, err = readInput()
+		} else {
+			var data []byte
+			data, err = io.ReadAll(os.Stdin)
+			userInput = string(data)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		output, err := formatJSON(userInput)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		writeOutput(output)
+	},
+}
+
+func init() {
+	addSourceFlags(fmtCmd.Flags())
+	rootCmd.AddCommand(fmtCmd)
+}
+
+// formatJSON indents userInput with two spaces. Invalid input is reported with the same errors as for generating
+// types.
+func formatJSON(userInput string) (string, error) {
+	userInput = strings.TrimPrefix(userInput, "\ufeff")
+	if _, err := parse.ParseFromString(userInput); err != nil {
+		return "", err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(strings.TrimSpace(userInput)), "", "  "); err != nil {
+		return "", err
+	}
+	return indented.String(), nil
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import "testing"
+
+func TestFormatJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: `{"b":[1,2],"a":null}`, want: "{\n  \"b\": [\n    1,\n    2\n  ],\n  \"a\": null\n}"},
+		{input: "\ufeff[]\n", want: "[]"},
+		{input: `{"a": 1,}`, wantErr: true},
+		{input: `{"a": 1} {}`, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := formatJSON(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("formatJSON(%q): got error %v, want error %v", test.input, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("formatJSON(%q): got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/config"
+	"github.com/marhaupe/json2struct/pkg/editor"
+	"github.com/marhaupe/json2struct/pkg/generator"
+	"github.com/marhaupe/json2struct/pkg/jsonschema"
+	"github.com/marhaupe/json2struct/pkg/openapi"
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	validateTags     bool
+	validateMethod   bool
+	withExamples     bool
+	withExampleStats bool
+	testFile         string
+	updateFile       string
+	generateAll      bool
+	outputFile       string
+	shouldWatch      bool
+
+	generateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "generate Go types for a JSON, YAML or TOML sample, a JSON Schema or an OpenAPI document",
+		Long: "generate generates Go types, or with --format a JSON Schema, TypeScript or Protocol Buffers, for the " +
+			"input given by --string, --file, --url or --clipboard. Without any of them, it opens a text editor. The " +
+			"defaults of " + config.FileName + " apply to the flags that aren't given, and --all runs its jobs instead.",
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			switch {
+			case shouldWatch:
+				runWatch(cmd.Flags())
+			case generateAll:
+				runGenerateAll()
+			default:
+				runGenerateWithConfig(cmd.Flags())
+			}
+		},
+	}
+)
+
+func init() {
+	addGenerateFlags(generateCmd.Flags())
+	generateCmd.Flags().BoolVar(&generateAll, "all", false, "run every job of "+config.FileName+" in parallel")
+	generateCmd.Flags().StringVarP(&outputFile, "output", "o", "", "write the generated code to this file instead of stdout")
+	generateCmd.Flags().BoolVarP(&shouldWatch, "watch", "w", false, "regenerate --output, or the outputs of the jobs with --all, whenever the input changes")
+	rootCmd.AddCommand(generateCmd)
+}
+
+func addGenerateFlags(flags *pflag.FlagSet) {
+	addInputFlags(flags)
+	addTypeFlags(flags)
+	addGoFlags(flags)
+	flags.StringVar(&outputFormat, "format", "go", "format of the output: go, jsonschema, typescript or proto")
+	flags.BoolVar(&validateTags, "validate-tags", false, "add validate:\"required\" tags to the fields present in every sample")
+	flags.BoolVar(&validateMethod, "validate-method", false, "add a Validate method checking required fields and enum-like strings")
+	flags.BoolVar(&withExamples, "examples", false, "add a comment with an example value to every field")
+	flags.BoolVar(&withExampleStats, "example-stats", false, "add a comment with how often every field was observed and null")
+	flags.StringVar(&testFile, "test-file", "", "write a test checking that the input round-trips through the generated types to this file, e.g. types_test.go")
+	flags.StringVar(&updateFile, "update", "", "merge the input into the types of this existing Go file instead of printing new ones, e.g. types.go")
+	flags.BoolVarP(&shouldBenchmark, "benchmark", "b", false, "measure execution time")
+}
+
+// runGenerateWithConfig applies the defaults of the config file to flags and runs runGenerate.
+func runGenerateWithConfig(flags *pflag.FlagSet) {
+	if err := applyConfig(flags); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	runGenerate()
+}
+
+// runGenerate generates the types for the input and prints them, reading the input from a text editor if none
+// was given.
+func runGenerate() {
+	if shouldBenchmark {
+		defer benchmark()()
+	}
+
+	var result *generator.Result
+	var err error
+
+	if hasInput() {
+		var userInput string
+		userInput, err = readInput()
+		if err == nil {
+			result, err = generate(userInput)
+		}
+	} else {
+		result, err = readFromEditor()
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if result == nil {
+		return
+	}
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, []byte(result.Code), 0o644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+	writeOutput(result.Code)
+}
+
+// generate generates the Go type definitions for the user input, which is interpreted according to --input-format.
+func generate(userInput string) (*generator.Result, error) {
+	generatorOptions, err := makeGeneratorOptions()
+	if err != nil {
+		return nil, err
+	}
+	parseOptions, err := makeParseOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := parse.ParsePath(subtreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	format := detectInputFormat()
+	if testFile != "" && (format != "json" || isLenient || generatorOptions.Format != generator.FormatGo || selector != nil) {
+		return nil, fmt.Errorf("--test-file needs plain JSON input, Go output and no --path")
+	}
+	if updateFile != "" && (!isSampleFormat(format) || generatorOptions.Format != generator.FormatGo) {
+		return nil, fmt.Errorf("--update needs a JSON, YAML or TOML sample and Go output")
+	}
+
+	if updateFile != "" {
+		samples, err := parseSamples(userInput, format, selector, parseOptions)
+		if err != nil {
+			return nil, err
+		}
+		return nil, update(samples, withFormatTag(generatorOptions, format))
+	}
+	result, err := generateFrom(userInput, format, selector, parseOptions, generatorOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+	if testFile != "" {
+		return result, writeTest(userInput, generatorOptions)
+	}
+	return result, nil
+}
+
+// generateFrom generates the types for userInput, which is in the given format, or for the subtree of a sample
+// selected by selector. It doesn't depend on any flags, so that it can run concurrently.
+func generateFrom(userInput, format string, selector parse.Path, parseOpts parse.Options, opts generator.Options) (*generator.Result, error) {
+	if selector != nil && !isSampleFormat(format) {
+		return nil, fmt.Errorf("a path can only select a subtree of a JSON, YAML or TOML sample, not of %v", format)
+	}
+	switch format {
+	case "json", "yaml", "toml":
+		samples, err := parseSamples(userInput, format, selector, parseOpts)
+		if err != nil {
+			return nil, err
+		}
+		return generator.GenerateFromSamples(samples, withFormatTag(opts, format))
+	case "jsonschema":
+		doc, err := jsonschema.Parse([]byte(userInput))
+		if err != nil {
+			return nil, err
+		}
+		return generator.GenerateFromSchema(doc, opts)
+	case "openapi":
+		doc, err := openapi.Parse([]byte(userInput))
+		if err != nil {
+			return nil, err
+		}
+		// The examples are parsed like samples. Their warnings are returned in the result instead.
+		parseOpts.Warn = nil
+		return generator.GenerateFromOpenAPI(doc, parseOpts, opts)
+	default:
+		return nil, fmt.Errorf("invalid input format %q. expected json, yaml, toml, jsonschema or openapi", format)
+	}
+}
+
+// parseSamples parses a sample in the given format and returns the nodes selected by selector. Several nodes,
+// e.g. the elements of an array, are samples of the same data.
+func parseSamples(userInput, format string, selector parse.Path, opts parse.Options) ([]parse.Node, error) {
+	userInputNode, err := parseSample(userInput, format, opts)
+	if err != nil {
+		return nil, err
+	}
+	return selector.Select(userInputNode)
+}
+
+// isSampleFormat reports whether format is the format of a sample rather than a schema.
+func isSampleFormat(format string) bool {
+	return format == "json" || format == "yaml" || format == "toml"
+}
+
+// withFormatTag adds a tag for the format of a sample to opts, since the generated types are most likely
+// decoded from the same format. Tags from --tag take precedence. Only Go output has tags.
+func withFormatTag(opts generator.Options, format string) generator.Options {
+	if format != "json" && opts.Format == generator.FormatGo {
+		opts.Tags = append([]generator.Tag{{Name: format}}, opts.Tags...)
+	}
+	return opts
+}
+
+// writeTest writes the test generated for the input to --test-file.
+func writeTest(userInput string, opts generator.Options) error {
+	test, err := generator.GenerateTest(userInput, opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(testFile, []byte(test.Code), 0o644)
+}
+
+// update merges the types inferred from the input into the existing file passed to --update and overwrites it.
+func update(samples []parse.Node, opts generator.Options) error {
+	existing, err := os.ReadFile(updateFile)
+	if err != nil {
+		return err
+	}
+	result, err := generator.UpdateFromSamples(string(existing), samples, opts)
+	if err != nil {
+		return fmt.Errorf("%v: %v", updateFile, err)
+	}
+	if err := os.WriteFile(updateFile, []byte(result.Code), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "updated", updateFile)
+	return nil
+}
+
+func readFromEditor() (*generator.Result, error) {
+	edit := editor.New()
+	defer edit.Delete()
+	edit.Display()
+
+	userInput, _ := edit.Read()
+
+	result, err := generate(userInput)
+	if err == nil {
+		return result, nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("You supplied invalid input. Continue editing? (Y/n) ")
+		userAnswer, _ := reader.ReadString('\n')
+		userAnswer = strings.TrimSpace(userAnswer)
+		userWantsFix := len(userAnswer) == 0 || userAnswer[0] == 'y'
+		if !userWantsFix {
+			return nil, nil
+		}
+		fmt.Print("\033[1A\033[2K")
+		edit.Display()
+		userInput, _ = edit.Read()
+		result, err = generate(userInput)
+		if err == nil {
+			return result, nil
+		}
+	}
+}
@@ -1,43 +1,61 @@
 package cmd
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
-	"github.com/marhaupe/json2struct/pkg/editor"
+	"github.com/marhaupe/json2struct/pkg/fetch"
 	"github.com/marhaupe/json2struct/pkg/generator"
 	"github.com/marhaupe/json2struct/pkg/parse"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	inputString        string
 	inputFile          string
+	inputURL           string
+	requestMethod      string
+	requestHeaders     []string
+	requestBody        string
 	version            string
 	shouldBenchmark    bool
 	shouldUseClipboard bool
+	isLenient          bool
+	duplicateKeys      string
+	fieldOrder         string
+	packageName        string
+	typeName           string
+	inputFormat        string
+	outputFormat       string
+	tags               []string
+	subtreePath        string
+	typeOverrides      []string
 
 	rootCmd = &cobra.Command{
-		Use:     "json2struct",
-		Short:   "json2struct generates Go type definitions for a JSON",
+		Use:   "json2struct",
+		Short: "json2struct generates Go type definitions for a JSON",
+		Long: "json2struct generates Go type definitions for a JSON. Without a command, it runs generate, " +
+			"which is kept for compatibility with earlier versions.",
 		Version: version,
 		Args:    cobra.ExactArgs(0),
 		Run: func(cmd *cobra.Command, args []string) {
-			Run(inputString, inputFile, shouldBenchmark, shouldUseClipboard)
+			runGenerateWithConfig(cmd.Flags())
 		},
 	}
 )
 
 func init() {
-	rootCmd.Flags().StringVarP(&inputString, "string", "s", "", "JSON string")
-	rootCmd.Flags().StringVarP(&inputFile, "file", "f", "", "path to JSON file")
-	rootCmd.Flags().BoolVarP(&shouldBenchmark, "benchmark", "b", false, "measure execution time")
-	rootCmd.Flags().BoolVarP(&shouldUseClipboard, "clipboard", "c", false, "read from and write types to clipboard")
+	// The flags of generate are documented there, and only accepted here for compatibility.
+	addGenerateFlags(rootCmd.Flags())
+	rootCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		flag.Hidden = true
+	})
 }
 
 func Execute() {
@@ -47,52 +65,74 @@ func Execute() {
 	}
 }
 
-func Run(inputString, inputFile string, shouldBenchmark, shouldUseClipboard bool) {
-	if shouldBenchmark {
-		defer benchmark()()
-	}
+// addSourceFlags adds the flags choosing where the input is read from.
+func addSourceFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&inputString, "string", "s", "", "JSON string")
+	flags.StringVarP(&inputFile, "file", "f", "", "path to JSON file")
+	flags.BoolVarP(&shouldUseClipboard, "clipboard", "c", false, "read from and write the output to clipboard")
+	flags.StringVar(&inputURL, "url", "", "URL to fetch the JSON from")
+	flags.StringVarP(&requestMethod, "method", "X", "", "HTTP method of the request to --url (default: POST with --data, otherwise GET)")
+	flags.StringArrayVarP(&requestHeaders, "header", "H", nil, "header of the request to --url as 'Name: value'. $NAME and ${NAME} are replaced by environment variables")
+	flags.StringVarP(&requestBody, "data", "d", "", "body of the request to --url")
+}
+
+// addInputFlags adds the flags choosing where the input is read from and how it's parsed.
+func addInputFlags(flags *pflag.FlagSet) {
+	addSourceFlags(flags)
+	flags.StringVar(&inputFormat, "input-format", "", "format of the input: json, yaml, toml, jsonschema or openapi (default: detected from the file extension, otherwise json)")
+	flags.BoolVarP(&isLenient, "lenient", "l", false, "accept JSONC/JSON5 input, e.g. comments and trailing commas")
+	flags.StringVar(&subtreePath, "path", "", "generate the types for the subtree at this JSONPath or JSON Pointer only, e.g. data.items[*] or /data/items")
+	addDuplicateKeysFlag(flags)
+}
 
-	var userInputNode parse.Node
-	var err error
+func addDuplicateKeysFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&duplicateKeys, "duplicate-keys", "merge", "handling of duplicate keys within an object: merge, last or error")
+}
+
+// addTypeFlags adds the flags shaping the generated types in any output format.
+func addTypeFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&typeName, "type", "", "name of the generated root type (default: derived from --path, otherwise JSONToStruct)")
+	addFieldOrderFlag(flags)
+}
+
+func addFieldOrderFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&fieldOrder, "field-order", "alpha", "order of the generated fields: alpha or source")
+}
+
+// addGoFlags adds the flags shaping generated Go code.
+func addGoFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&packageName, "package", "generated", "package name of the generated file")
+	flags.StringArrayVar(&tags, "tag", nil, "further struct tag as name[:naming][:modifier...], e.g. db:snake or bson:camel:omitempty. naming is original, snake or camel")
+	flags.StringArrayVar(&typeOverrides, "override", nil, "Go type of the fields of a key as key=type, e.g. created_at=time.Time or id=github.com/google/uuid.UUID")
+}
+
+// hasInput reports whether --string, --file, --url or --clipboard was given.
+func hasInput() bool {
+	return shouldUseClipboard || inputFile != "" || inputURL != "" || inputString != ""
+}
 
+// readInput reads the input from --clipboard, --file, --url or --string, in that order.
+func readInput() (string, error) {
 	switch {
 	case shouldUseClipboard:
-		var userInput string
-		userInput, err = clipboard.ReadAll()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(2)
-		}
-		userInputNode, err = parse.ParseFromString(userInput)
+		return clipboard.ReadAll()
 	case inputFile != "":
-		userInput := readFromFile()
-		userInputNode, err = parse.ParseFromString(userInput)
-	case inputString != "":
-		userInput := inputString
-		userInputNode, err = parse.ParseFromString(userInput)
+		data, err := os.ReadFile(inputFile)
+		return string(data), err
+	case inputURL != "":
+		data, err := fetch.Fetch(fetch.Request{URL: inputURL, Method: requestMethod, Headers: requestHeaders, Body: requestBody})
+		return string(data), err
 	default:
-		userInputNode, err = readFromEditor()
-	}
-
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-
-	if userInputNode == nil {
-		return
-	}
-
-	output, err := generator.GenerateOutputFromAST(userInputNode)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(3)
+		return inputString, nil
 	}
+}
 
+// writeOutput prints the output, and copies it to the clipboard if the input was read from there.
+func writeOutput(output string) {
 	fmt.Println(output)
 
 	if shouldUseClipboard {
-		err = clipboard.WriteAll(output)
+		err := clipboard.WriteAll(output)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(4)
@@ -101,44 +141,115 @@ func Run(inputString, inputFile string, shouldBenchmark, shouldUseClipboard bool
 	}
 }
 
-func readFromFile() string {
-	data, err := os.ReadFile(inputFile)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+// detectInputFormat returns --input-format, or the format matching the extension of --file or the path of --url
+// if it's not set.
+func detectInputFormat() string {
+	if inputFormat != "" {
+		return inputFormat
 	}
-	return string(data)
+	if inputFile == "" && inputURL != "" {
+		if u, err := url.Parse(inputURL); err == nil {
+			return formatOfFile(u.Path)
+		}
+	}
+	return formatOfFile(inputFile)
 }
 
-func readFromEditor() (parse.Node, error) {
-	edit := editor.New()
-	defer edit.Delete()
-	edit.Display()
+// formatOfFile returns the format matching the extension of file, i.e. yaml, toml or json.
+func formatOfFile(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	default:
+		return "json"
+	}
+}
 
-	userInput, _ := edit.Read()
+// makeParseOptions returns the options of the parser according to the flags.
+func makeParseOptions() (parse.Options, error) {
+	policy, err := parse.ParseDuplicateKeyPolicy(duplicateKeys)
+	if err != nil {
+		return parse.Options{}, err
+	}
+	return parse.Options{
+		Lenient:       isLenient,
+		DuplicateKeys: policy,
+		// Validation and examples are derived from the values of the samples.
+		KeepValues: validateTags || validateMethod || withExamples,
+		Warn: func(msg string) {
+			fmt.Fprintln(os.Stderr, "warning:", msg)
+		},
+	}, nil
+}
 
-	userInputNode, err := parse.ParseFromString(userInput)
-	if err == nil {
-		return userInputNode, nil
+// parseSample parses a sample in the given format, i.e. json, yaml or toml.
+func parseSample(userInput, format string, opts parse.Options) (parse.Node, error) {
+	switch format {
+	case "yaml":
+		return parse.ParseYAML(userInput, opts)
+	case "toml":
+		return parse.ParseTOML(userInput, opts)
+	default:
+		return parse.ParseFromStringWithOptions(userInput, opts)
 	}
+}
 
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Print("You supplied invalid JSON. Continue editing? (Y/n) ")
-		userAnswer, _ := reader.ReadString('\n')
-		userAnswer = strings.TrimSpace(userAnswer)
-		userWantsFix := len(userAnswer) == 0 || userAnswer[0] == 'y'
-		if !userWantsFix {
-			return nil, nil
+func makeGeneratorOptions() (generator.Options, error) {
+	order, err := generator.ParseFieldOrder(fieldOrder)
+	if err != nil {
+		return generator.Options{}, err
+	}
+	format, err := generator.ParseFormat(outputFormat)
+	if err != nil {
+		return generator.Options{}, err
+	}
+	var generatorTags []generator.Tag
+	for _, s := range tags {
+		tag, err := generator.ParseTag(s)
+		if err != nil {
+			return generator.Options{}, err
 		}
-		fmt.Print("\033[1A\033[2K")
-		edit.Display()
-		userInput, _ = edit.Read()
-		isValid := json.Valid([]byte(userInput))
-		if isValid {
-			return userInputNode, nil
+		generatorTags = append(generatorTags, tag)
+	}
+	overrides, err := parseTypeOverrides(typeOverrides)
+	if err != nil {
+		return generator.Options{}, err
+	}
+	name := typeName
+	if name == "" {
+		name = "JSONToStruct"
+		if p, err := parse.ParsePath(subtreePath); err == nil && generator.TypeNameForPath(p) != "" {
+			name = generator.TypeNameForPath(p)
+		}
+	}
+	return generator.Options{
+		PackageName:    packageName,
+		TypeName:       name,
+		FieldOrder:     order,
+		Format:         format,
+		Tags:           generatorTags,
+		ValidateTags:   validateTags,
+		ValidateMethod: validateMethod,
+		Examples:       withExamples,
+		ExampleStats:   withExampleStats,
+		TypeOverrides:  overrides,
+	}, nil
+}
+
+// parseTypeOverrides parses the values of --override, i.e. key=type.
+func parseTypeOverrides(values []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, value := range values {
+		// Keys may contain =, types can't.
+		i := strings.LastIndex(value, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid override %q. expected key=type", value)
 		}
+		overrides[value[:i]] = value[i+1:]
 	}
+	return overrides, nil
 }
 
 func benchmark() func() {
@@ -1,31 +1,118 @@
 package cmd
 
-import "testing"
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/generator"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+func TestRunGenerate(t *testing.T) {
+	defer func(s string) {
+		inputString = s
+	}(inputString)
 
-func TestRun(t *testing.T) {
-	type args struct {
-		inputString        string
-		inputFile          string
-		shouldBenchmark    bool
-		shouldUseClipboard bool
-	}
 	tests := []struct {
-		name string
-		args args
+		name        string
+		inputString string
 	}{
 		{
-			name: "should parse input string",
-			args: args{
-				inputString:        `{"name": "John", "age": 30}`,
-				inputFile:          "",
-				shouldBenchmark:    false,
-				shouldUseClipboard: false,
-			},
+			name:        "should parse input string",
+			inputString: `{"name": "John", "age": 30}`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			Run(tt.args.inputString, tt.args.inputFile, tt.args.shouldBenchmark, tt.args.shouldUseClipboard)
+			inputString = tt.inputString
+			runGenerate()
 		})
 	}
 }
+
+func TestGenerateFromOpenAPIParseOptions(t *testing.T) {
+	doc := `{
+		"openapi": "3.0.0",
+		"paths": {"/users": {"get": {"responses": {"200": {"content": {"application/json": {
+			"example": {"a": 1, "a": "x", "name": "John"}
+		}}}}}}}
+	}`
+
+	_, err := generateFrom(doc, "openapi", nil, parse.Options{DuplicateKeys: parse.DuplicateKeysError}, generator.Options{})
+	if err == nil || !strings.Contains(err.Error(), "duplicate key") {
+		t.Errorf("generateFrom(): expected an error for the duplicate key, got %v", err)
+	}
+
+	result, err := generateFrom(doc, "openapi", nil, parse.Options{DuplicateKeys: parse.DuplicateKeysLastWins}, generator.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "A    string"; !strings.Contains(result.Code, want) {
+		t.Errorf("generateFrom(): expected %q in\n%v", want, result.Code)
+	}
+}
+
+func TestDetectInputFormat(t *testing.T) {
+	defer func(format, file, u string) {
+		inputFormat, inputFile, inputURL = format, file, u
+	}(inputFormat, inputFile, inputURL)
+
+	tests := []struct {
+		format string
+		file   string
+		url    string
+		want   string
+	}{
+		{format: "", file: "", want: "json"},
+		{format: "", file: "config.yaml", want: "yaml"},
+		{format: "", file: "config.YML", want: "yaml"},
+		{format: "", file: "Cargo.toml", want: "toml"},
+		{format: "", file: "data.json", want: "json"},
+		{format: "json", file: "config.yaml", want: "json"},
+		{format: "", url: "https://example.com/api/users?page=2", want: "json"},
+		{format: "", url: "https://example.com/config.yaml?ref=main", want: "yaml"},
+		{format: "json", url: "https://example.com/config.yaml", want: "json"},
+	}
+	for _, test := range tests {
+		inputFormat, inputFile, inputURL = test.format, test.file, test.url
+		if got := detectInputFormat(); got != test.want {
+			t.Errorf("detectInputFormat() with --input-format %q, --file %q and --url %q: got %v, want %v", test.format, test.file, test.url, got, test.want)
+		}
+	}
+}
+
+func TestReadInputFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, `{"query": "`+string(body)+`"}`)
+	}))
+	defer server.Close()
+	t.Setenv("JSON2STRUCT_TEST_KEY", "secret")
+	defer func(u, method, body string, headers []string) {
+		inputURL, requestMethod, requestBody, requestHeaders = u, method, body, headers
+	}(inputURL, requestMethod, requestBody, requestHeaders)
+
+	inputURL, requestBody, requestHeaders = server.URL, "users", []string{"X-Api-Key: $JSON2STRUCT_TEST_KEY"}
+	if !hasInput() {
+		t.Fatalf("hasInput(): got false with --url")
+	}
+	got, err := readInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"query": "users"}`; got != want {
+		t.Errorf("readInput(): got %v, want %v", got, want)
+	}
+
+	requestMethod = http.MethodGet
+	if _, err := readInput(); err == nil {
+		t.Errorf("readInput(): expected an error for a failed request")
+	}
+}
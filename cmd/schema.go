@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "generate a JSON Schema for a JSON, YAML or TOML sample",
+	Long: "schema generates a JSON Schema (draft 2020-12) describing the structure of the input. It's a shortcut " +
+		"for generate --format jsonschema.",
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		if format := detectInputFormat(); format == "jsonschema" || format == "openapi" {
+			fmt.Printf("schema needs a JSON, YAML or TOML sample, not %v\n", format)
+			os.Exit(1)
+		}
+		outputFormat = "jsonschema"
+		runGenerateWithConfig(cmd.Flags())
+	},
+}
+
+func init() {
+	addInputFlags(schemaCmd.Flags())
+	addTypeFlags(schemaCmd.Flags())
+	schemaCmd.Flags().BoolVarP(&shouldBenchmark, "benchmark", "b", false, "measure execution time")
+	rootCmd.AddCommand(schemaCmd)
+}
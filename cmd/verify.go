@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/marhaupe/json2struct/pkg/generator"
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"github.com/marhaupe/json2struct/pkg/verify"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify sample.json...",
+	Short: "check that the types generated for JSON samples can decode all of them",
+	Long: "verify generates the Go types for all samples, type checks them and checks every sample against them " +
+		"the way encoding/json would decode it. It reports values that wouldn't decode or would be dropped, " +
+		"and exits with status 1 if there are any.",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		problems, err := runVerify(args)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println("every sample fits the generated types")
+	},
+}
+
+func init() {
+	addDuplicateKeysFlag(verifyCmd.Flags())
+	addTypeFlags(verifyCmd.Flags())
+	addGoFlags(verifyCmd.Flags())
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// runVerify verifies the samples in files and returns the problems found, prefixed with the file they were
+// found in.
+func runVerify(files []string) ([]string, error) {
+	policy, err := parse.ParseDuplicateKeyPolicy(duplicateKeys)
+	if err != nil {
+		return nil, err
+	}
+	generatorOptions, err := makeGeneratorOptions()
+	if err != nil {
+		return nil, err
+	}
+	generatorOptions.Format = generator.FormatGo
+
+	var samples []parse.Node
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		// Numbers are checked against the generated fields, which needs their values.
+		sample, err := parse.ParseFromStringWithOptions(string(data), parse.Options{DuplicateKeys: policy, KeepValues: true})
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file, err)
+		}
+		samples = append(samples, sample)
+	}
+
+	result, err := generator.GenerateFromSamples(samples, generatorOptions)
+	if err != nil {
+		return nil, err
+	}
+	typ, err := verify.TypeCheck(result.Code, generatorOptions.TypeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, problem := range verify.Samples(typ, samples) {
+		problems = append(problems, fmt.Sprintf("%v: %v", files[problem.Sample], problem))
+	}
+	return problems, nil
+}
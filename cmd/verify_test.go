@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunVerify(t *testing.T) {
+	dir := t.TempDir()
+	samples := map[string]string{
+		"first.json":  `{ "id": 1, "name": "a", "$schema": "x" }`,
+		"second.json": `{ "id": 10000000000000000999, "tags": ["b"] }`,
+	}
+	var files []string
+	for _, name := range []string{"first.json", "second.json"} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(samples[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	problems, err := runVerify(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		files[0] + `: $schema: unknown field "$schema"`,
+		files[1] + ": id: cannot unmarshal number 10000000000000000999 into int",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("runVerify(): got %q, want %q", problems, want)
+	}
+
+	if _, err := runVerify([]string{filepath.Join(dir, "missing.json")}); err == nil {
+		t.Errorf("runVerify(): expected an error for a missing file")
+	}
+}
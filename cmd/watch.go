@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/config"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// watchDebounce is how long the watcher waits for further changes before regenerating, since editors often
+	// write a file in several steps.
+	watchDebounce = 200 * time.Millisecond
+	// maxSummaryLines limits the lines of a summary of changes.
+	maxSummaryLines = 20
+)
+
+// watchTarget is an input that is regenerated on changes.
+type watchTarget struct {
+	input  string
+	output string
+	// generate generates and writes the output.
+	generate func() error
+}
+
+// runWatch regenerates the output of --file, or of every job with --all, whenever an input changes.
+func runWatch(flags *pflag.FlagSet) {
+	targets, err := makeWatchTargets(flags)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := watch(targets, watchDebounce, nil, func(msg string) { fmt.Println(msg) }); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func makeWatchTargets(flags *pflag.FlagSet) ([]watchTarget, error) {
+	if generateAll {
+		cfg, err := loadConfig()
+		if err == nil && cfg == nil {
+			err = config.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		var targets []watchTarget
+		for _, job := range cfg.Jobs {
+			targets = append(targets, watchTarget{
+				input:    cfg.Resolve(job.Input),
+				output:   cfg.Resolve(job.Output),
+				generate: func() error { return runJob(cfg, job) },
+			})
+		}
+		return targets, nil
+	}
+
+	if inputFile == "" || outputFile == "" || updateFile != "" {
+		return nil, errors.New("--watch needs --file and --output, or --all")
+	}
+	if err := applyConfig(flags); err != nil {
+		return nil, err
+	}
+	return []watchTarget{{
+		input:  inputFile,
+		output: outputFile,
+		generate: func() error {
+			userInput, err := readInput()
+			if err != nil {
+				return err
+			}
+			result, err := generate(userInput)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(outputFile, []byte(result.Code), 0o644)
+		},
+	}}, nil
+}
+
+// watch generates all targets, and regenerates a target whenever its input changes, once no further change
+// happened for debounce. It reports the outcome of every generation, and runs until stop is closed.
+func watch(targets []watchTarget, debounce time.Duration, stop <-chan struct{}, report func(string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// Editors often replace a file instead of writing it, which ends a watch of the file itself. That's why
+	// the directories of the inputs are watched instead.
+	targetsByInput := make(map[string][]watchTarget)
+	for _, target := range targets {
+		input, err := filepath.Abs(target.input)
+		if err != nil {
+			return err
+		}
+		if len(targetsByInput[input]) == 0 {
+			if err := watcher.Add(filepath.Dir(input)); err != nil {
+				return err
+			}
+		}
+		targetsByInput[input] = append(targetsByInput[input], target)
+	}
+
+	for _, target := range targets {
+		regenerate(target, report)
+	}
+
+	changed := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if input, err := filepath.Abs(event.Name); err == nil && len(targetsByInput[input]) > 0 {
+				changed[input] = true
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			report(fmt.Sprintf("error watching the inputs: %v", err))
+		case <-timer.C:
+			for input := range changed {
+				for _, target := range targetsByInput[input] {
+					regenerate(target, report)
+				}
+			}
+			changed = make(map[string]bool)
+		}
+	}
+}
+
+// regenerate generates target and reports what changed in its output.
+func regenerate(target watchTarget, report func(string)) {
+	previous, _ := os.ReadFile(target.output)
+	if err := target.generate(); err != nil {
+		report(fmt.Sprintf("%v: %v", target.input, err))
+		return
+	}
+	current, err := os.ReadFile(target.output)
+	if err != nil {
+		report(fmt.Sprintf("%v: %v", target.output, err))
+		return
+	}
+	changes := summarizeChanges(string(previous), string(current))
+	switch {
+	case len(previous) == 0:
+		report(fmt.Sprintf("%v: generated %v", target.input, target.output))
+	case len(changes) == 0:
+		report(fmt.Sprintf("%v: no changes to %v", target.input, target.output))
+	default:
+		report(fmt.Sprintf("%v: regenerated %v\n%v", target.input, target.output, strings.Join(changes, "\n")))
+	}
+}
+
+// summarizeChanges lists the lines added to and removed from the generated code, e.g. `+ Email string`.
+// Differences in whitespace, e.g. of aligned fields, are ignored.
+func summarizeChanges(previous, current string) []string {
+	var summary []string
+	for _, chunk := range diff.DiffChunks(normalizeLines(previous), normalizeLines(current)) {
+		for _, line := range chunk.Deleted {
+			summary = append(summary, "  - "+line)
+		}
+		for _, line := range chunk.Added {
+			summary = append(summary, "  + "+line)
+		}
+	}
+	if len(summary) > maxSummaryLines {
+		more := len(summary) - maxSummaryLines
+		summary = append(summary[:maxSummaryLines], fmt.Sprintf("  ... and %d more", more))
+	}
+	return summary
+}
+
+// normalizeLines returns the non-empty lines of code with their whitespace collapsed.
+func normalizeLines(code string) []string {
+	var lines []string
+	for _, line := range strings.Split(code, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, strings.Join(fields, " "))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarizeChanges(t *testing.T) {
+	previous := "package generated\n\ntype JSONToStruct struct {\n\tAge int `json:\"age\"`\n\tID  int `json:\"id\"`\n}\n"
+	current := "package generated\n\ntype JSONToStruct struct {\n\tEmail string `json:\"email\"`\n\tID    int    `json:\"id\"`\n}\n"
+	want := []string{
+		"  - Age int `json:\"age\"`",
+		"  + Email string `json:\"email\"`",
+	}
+	if got := summarizeChanges(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizeChanges(): got %q, want %q", got, want)
+	}
+	if got := summarizeChanges(previous, previous); len(got) != 0 {
+		t.Errorf("summarizeChanges() of equal code: got %q", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "input.json"), filepath.Join(dir, "output.txt")
+	if err := os.WriteFile(input, []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	target := watchTarget{
+		input:  input,
+		output: output,
+		generate: func() error {
+			data, err := os.ReadFile(input)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(output, []byte(strings.ToUpper(string(data))), 0o644)
+		},
+	}
+
+	reports := make(chan string, 10)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- watch([]watchTarget{target}, 100*time.Millisecond, stop, func(msg string) { reports <- msg })
+	}()
+
+	expectReport := func(want string) {
+		t.Helper()
+		select {
+		case got := <-reports:
+			if got != want {
+				t.Errorf("watch(): got report %q, want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("watch(): no report, want %q", want)
+		}
+	}
+	expectReport(input + ": generated " + output)
+
+	// A burst of writes is debounced into a single regeneration.
+	for _, content := range []string{"second", "third"} {
+		if err := os.WriteFile(input, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectReport(input + ": regenerated " + output + "\n  - FIRST\n  + THIRD")
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,213 @@
+// Package config reads .json2struct.yaml, the project config file that declares the defaults and jobs of
+// `json2struct generate`.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/generator"
+	"github.com/marhaupe/json2struct/pkg/parse"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file.
+const FileName = ".json2struct.yaml"
+
+// ErrNotFound is returned by Find if neither the directory nor any of its parents contain a config file.
+var ErrNotFound = errors.New("no " + FileName + " found")
+
+// Config is the content of a config file.
+type Config struct {
+	// Path is the path of the config file. Paths of jobs are relative to its directory.
+	Path string `yaml:"-"`
+	// Defaults apply to every job, and to generate without --all unless overridden by a flag.
+	Defaults Settings `yaml:"defaults"`
+	Jobs     []Job    `yaml:"jobs"`
+}
+
+// Settings are the options shared by jobs. Empty settings are left to the next level, i.e. from a job to the
+// defaults, and from the defaults to the defaults of the generator.
+type Settings struct {
+	// Package is the package name of the generated files.
+	Package string `yaml:"package"`
+	// FieldOrder is the order of the generated fields: alpha or source.
+	FieldOrder string `yaml:"field_order"`
+	// Naming is the naming of the Tags that don't specify one: original, snake or camel.
+	Naming string `yaml:"naming"`
+	// Tags are further struct tags as name[:naming][:modifier...], just like --tag.
+	Tags []string `yaml:"tags"`
+	// Overrides map keys to the Go type of their fields, e.g. created_at to time.Time.
+	Overrides map[string]string `yaml:"overrides"`
+}
+
+// Job generates the types for a single input.
+type Job struct {
+	// Input is the path of the sample or schema.
+	Input string `yaml:"input"`
+	// InputFormat is the format of the input: json, yaml, toml, jsonschema or openapi. Defaults to the format
+	// matching the extension of Input, otherwise json.
+	InputFormat string `yaml:"input_format"`
+	// Output is the path of the generated Go file.
+	Output string `yaml:"output"`
+	// Path selects the subtree of the input to generate the types for, just like --path.
+	Path string `yaml:"path"`
+	// Type is the name of the root type. Defaults to a name derived from Path, otherwise the default of the
+	// generator.
+	Type string `yaml:"type"`
+	// Settings override the defaults for this job. Tags and overrides are added to those of the defaults.
+	Settings `yaml:",inline"`
+}
+
+// Find returns the path of the config file in dir or the closest of its parents.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNotFound
+		}
+		dir = parent
+	}
+}
+
+// Load reads and checks the config file at path. Unknown keys are rejected to point out typos.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	config := &Config{Path: path}
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+
+	if _, err := config.Defaults.options(); err != nil {
+		return nil, fmt.Errorf("%v: defaults: %v", path, err)
+	}
+	for i, job := range config.Jobs {
+		if job.Input == "" || job.Output == "" {
+			return nil, fmt.Errorf("%v: job %d: input and output are required", path, i+1)
+		}
+		if _, err := config.Options(job); err != nil {
+			return nil, fmt.Errorf("%v: job %d: %v", path, i+1, err)
+		}
+	}
+	return config, nil
+}
+
+// Resolve returns path relative to the directory of the config file, unless it's absolute.
+func (c *Config) Resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(c.Path), path)
+}
+
+// Options returns the options of the generator for job, which are its settings on top of the defaults.
+func (c *Config) Options(job Job) (generator.Options, error) {
+	opts, err := c.Defaults.Merge(job.Settings).options()
+	if err != nil {
+		return generator.Options{}, err
+	}
+	p, err := parse.ParsePath(job.Path)
+	if err != nil {
+		return generator.Options{}, err
+	}
+	opts.TypeName = job.Type
+	if opts.TypeName == "" {
+		opts.TypeName = generator.TypeNameForPath(p)
+	}
+	return opts, nil
+}
+
+// Merge returns s with the settings of other on top, adding their tags and overrides to those of s.
+func (s Settings) Merge(other Settings) Settings {
+	merged := s
+	if other.Package != "" {
+		merged.Package = other.Package
+	}
+	if other.FieldOrder != "" {
+		merged.FieldOrder = other.FieldOrder
+	}
+	if other.Naming != "" {
+		merged.Naming = other.Naming
+	}
+	merged.Tags = append(append([]string{}, s.Tags...), other.Tags...)
+	merged.Overrides = make(map[string]string, len(s.Overrides)+len(other.Overrides))
+	for _, overrides := range []map[string]string{s.Overrides, other.Overrides} {
+		for key, override := range overrides {
+			merged.Overrides[key] = override
+		}
+	}
+	return merged
+}
+
+// TagFlags returns the tags in the syntax of --tag, with Naming added to those that don't specify a naming.
+func (s Settings) TagFlags() []string {
+	var flags []string
+	for _, tag := range s.Tags {
+		parts := strings.Split(tag, ":")
+		hasNaming := false
+		for _, part := range parts[1:] {
+			if _, err := generator.ParseNaming(part); err == nil {
+				hasNaming = true
+			}
+		}
+		// The json tag has to keep the original keys.
+		if s.Naming != "" && !hasNaming && parts[0] != "json" {
+			tag = strings.Join(append([]string{parts[0], s.Naming}, parts[1:]...), ":")
+		}
+		flags = append(flags, tag)
+	}
+	return flags
+}
+
+// OverrideFlags returns the overrides in the syntax of --override, i.e. key=type, sorted by key.
+func (s Settings) OverrideFlags() []string {
+	var flags []string
+	for key, override := range s.Overrides {
+		flags = append(flags, key+"="+override)
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+func (s Settings) options() (generator.Options, error) {
+	opts := generator.Options{PackageName: s.Package, TypeOverrides: s.Overrides}
+	if s.FieldOrder != "" {
+		order, err := generator.ParseFieldOrder(s.FieldOrder)
+		if err != nil {
+			return generator.Options{}, err
+		}
+		opts.FieldOrder = order
+	}
+	if s.Naming != "" {
+		if _, err := generator.ParseNaming(s.Naming); err != nil {
+			return generator.Options{}, err
+		}
+	}
+	for _, flag := range s.TagFlags() {
+		tag, err := generator.ParseTag(flag)
+		if err != nil {
+			return generator.Options{}, err
+		}
+		opts.Tags = append(opts.Tags, tag)
+	}
+	return opts, nil
+}
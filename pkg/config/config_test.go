@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/generator"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Find(nested); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find() without a config file: got error %v, want %v", err, ErrNotFound)
+	}
+
+	want := writeConfig(t, root, "jobs: []")
+	for _, dir := range []string{root, filepath.Join(root, "a"), nested} {
+		got, err := Find(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Find(%v): got %v, want %v", dir, got, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, `
+defaults:
+  package: api
+  field_order: source
+  naming: snake
+  tags: [db, "json:omitempty", "bson:camel"]
+  overrides:
+    created_at: time.Time
+jobs:
+  - input: samples/user.json
+    output: api/user.go
+    type: User
+  - input: samples/order.json
+    output: orders/order.go
+    type: Order
+    package: orders
+    field_order: alpha
+    tags: [yaml]
+    overrides:
+      id: string
+`)
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Jobs) != 2 {
+		t.Fatalf("Load(): got %d jobs, want 2", len(config.Jobs))
+	}
+	if got, want := config.Resolve(config.Jobs[0].Input), filepath.Join(dir, "samples", "user.json"); got != want {
+		t.Errorf("Resolve(): got %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		job  Job
+		want generator.Options
+	}{
+		{
+			job: config.Jobs[0],
+			want: generator.Options{
+				PackageName: "api",
+				TypeName:    "User",
+				FieldOrder:  generator.FieldOrderSource,
+				Tags: []generator.Tag{
+					{Name: "db", Naming: generator.NamingSnake},
+					{Name: "json", Modifiers: []string{"omitempty"}},
+					{Name: "bson", Naming: generator.NamingCamel},
+				},
+				TypeOverrides: map[string]string{"created_at": "time.Time"},
+			},
+		},
+		{
+			job: config.Jobs[1],
+			want: generator.Options{
+				PackageName: "orders",
+				TypeName:    "Order",
+				FieldOrder:  generator.FieldOrderAlphabetical,
+				Tags: []generator.Tag{
+					{Name: "db", Naming: generator.NamingSnake},
+					{Name: "json", Modifiers: []string{"omitempty"}},
+					{Name: "bson", Naming: generator.NamingCamel},
+					{Name: "yaml", Naming: generator.NamingSnake},
+				},
+				TypeOverrides: map[string]string{"created_at": "time.Time", "id": "string"},
+			},
+		},
+	}
+	for _, test := range tests {
+		got, err := config.Options(test.job)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Options(%v): got %+v, want %+v", test.job.Input, got, test.want)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown key", content: "defaults:\n  pakage: api\n"},
+		{name: "missing output", content: "jobs:\n  - input: a.json\n"},
+		{name: "invalid field order", content: "defaults:\n  field_order: random\n"},
+		{name: "invalid naming", content: "defaults:\n  naming: kebab\n"},
+		{name: "invalid tag", content: "jobs:\n  - input: a.json\n    output: a.go\n    tags: [\"json:snake\"]\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, t.TempDir(), test.content)); err == nil {
+				t.Errorf("Load(): expected an error")
+			}
+		})
+	}
+
+	if _, err := Load(writeConfig(t, t.TempDir(), "")); err != nil {
+		t.Errorf("Load() of an empty file: %v", err)
+	}
+}
+
+func TestOptionsTypeFromPath(t *testing.T) {
+	config := &Config{}
+	tests := []struct {
+		job  Job
+		want string
+	}{
+		{job: Job{Path: "data.items[*]"}, want: "Item"},
+		{job: Job{Path: "data.items[*]", Type: "Product"}, want: "Product"},
+		{job: Job{}, want: ""},
+	}
+	for _, test := range tests {
+		opts, err := config.Options(test.job)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.TypeName != test.want {
+			t.Errorf("Options(%+v): got type %q, want %q", test.job, opts.TypeName, test.want)
+		}
+	}
+	if _, err := config.Options(Job{Path: "data["}); err == nil {
+		t.Errorf("Options(): expected an error for an invalid path")
+	}
+}
@@ -0,0 +1,107 @@
+// Package fetch downloads inputs over HTTP.
+package fetch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout limits requests of the default client.
+const DefaultTimeout = 30 * time.Second
+
+// maxErrorBodyLength limits how much of the body of a failed response is included in the error.
+const maxErrorBodyLength = 200
+
+// Request describes a single HTTP request. Responses are never paginated, i.e. only the body of this request is
+// returned.
+type Request struct {
+	URL string
+	// Method is the HTTP method. Defaults to POST if there's a Body, otherwise GET.
+	Method string
+	// Headers are sent as written on the command line, i.e. `Name: value`. Values may reference environment
+	// variables as $NAME or ${NAME}, which keeps secrets like tokens out of the command line.
+	Headers []string
+	// Body is sent as the body of the request.
+	Body string
+	// Client sends the request. Defaults to a client with DefaultTimeout.
+	Client *http.Client
+}
+
+// Fetch sends r and returns the body of the response. Responses with a status other than 2xx are errors.
+func Fetch(r Request) ([]byte, error) {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+		if r.Body != "" {
+			method = http.MethodPost
+		}
+	}
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(r.Body)
+	}
+	req, err := http.NewRequest(method, r.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for _, header := range r.Headers {
+		name, value, err := parseHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(name, value)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%v %v: error reading the response: %v", method, r.URL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > maxErrorBodyLength {
+			msg = msg[:maxErrorBodyLength] + "..."
+		}
+		if msg == "" {
+			return nil, fmt.Errorf("%v %v: %v", method, r.URL, resp.Status)
+		}
+		return nil, fmt.Errorf("%v %v: %v: %v", method, r.URL, resp.Status, msg)
+	}
+	return data, nil
+}
+
+// parseHeader parses a header as written on the command line, i.e. `Name: value`, and expands the environment
+// variables in its value. Unset variables are errors, since they'd most likely make the request fail anyway.
+func parseHeader(header string) (name, value string, err error) {
+	name, value, ok := strings.Cut(header, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid header %q. expected Name: value", header)
+	}
+	var missing []string
+	value = os.Expand(strings.TrimSpace(value), func(variable string) string {
+		v, ok := os.LookupEnv(variable)
+		if !ok {
+			missing = append(missing, variable)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", "", fmt.Errorf("the header %v references unset environment variables: %v", name, strings.Join(missing, ", "))
+	}
+	return name, value, nil
+}
@@ -0,0 +1,94 @@
+package fetch
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/echo":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"method": "`+r.Method+`", "auth": "`+r.Header.Get("Authorization")+`", "accept": "`+r.Header.Get("Accept")+`", "body": "`+string(body)+`"}`)
+		case "/missing":
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("JSON2STRUCT_TEST_TOKEN", "secret")
+
+	tests := []struct {
+		name    string
+		request Request
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "get",
+			request: Request{URL: server.URL + "/echo"},
+			want:    `{"method": "GET", "auth": "", "accept": "application/json", "body": ""}`,
+		},
+		{
+			name: "headers from env",
+			request: Request{
+				URL:     server.URL + "/echo",
+				Headers: []string{"Authorization: Bearer ${JSON2STRUCT_TEST_TOKEN}", "Accept:text/plain"},
+			},
+			want: `{"method": "GET", "auth": "Bearer secret", "accept": "text/plain", "body": ""}`,
+		},
+		{
+			name:    "body",
+			request: Request{URL: server.URL + "/echo", Body: "query"},
+			want:    `{"method": "POST", "auth": "", "accept": "application/json", "body": "query"}`,
+		},
+		{
+			name:    "method",
+			request: Request{URL: server.URL + "/echo", Method: "PUT", Body: "query"},
+			want:    `{"method": "PUT", "auth": "", "accept": "application/json", "body": "query"}`,
+		},
+		{
+			name:    "status with body",
+			request: Request{URL: server.URL + "/missing"},
+			wantErr: `404 Not Found: {"error": "not found"}`,
+		},
+		{
+			name:    "status without body",
+			request: Request{URL: server.URL + "/fail"},
+			wantErr: "500 Internal Server Error",
+		},
+		{
+			name:    "unset variable",
+			request: Request{URL: server.URL + "/echo", Headers: []string{"Authorization: Bearer $JSON2STRUCT_TEST_UNSET"}},
+			wantErr: "unset environment variables: JSON2STRUCT_TEST_UNSET",
+		},
+		{
+			name:    "invalid header",
+			request: Request{URL: server.URL + "/echo", Headers: []string{"Authorization"}},
+			wantErr: "invalid header",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Fetch(test.request)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("Fetch(): got error %v, want an error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("Fetch(): got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+	"github.com/marhaupe/json2struct/pkg/parse"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	defaultEnvelopeName = "Envelope"
+	typeParameterName   = "T"
+)
+
+// conventionalPayloadKeys are keys that usually hold the payload of an envelope, in the order they are preferred in
+// when the samples don't tell which key it is.
+var conventionalPayloadKeys = []string{"data", "payload", "result", "results", "items", "body", "response"}
+
+// EnvelopeSample is a sample of an API whose responses share an envelope around a varying payload, e.g.
+// `{"status": "ok", "error": null, "data": {...}}`.
+type EnvelopeSample struct {
+	// Name names the payload type of the sample, e.g. `user` or `list-orders` for User and ListOrders. Samples
+	// with the same name are merged into the same payload type.
+	Name string
+	Tree parse.Node
+}
+
+// payloadGroup is the payload of all samples with the same name.
+type payloadGroup struct {
+	name     string
+	typeName string
+	samples  []*parse.ObjectNode
+}
+
+// GenerateEnvelope generates a generic Go type for the envelope shared by samples, whose type parameter is the type
+// of the payload, e.g. `Envelope[T any]`. For every name of the samples, it generates the type of their payload and
+// an alias for the envelope holding it, e.g. User and `UserEnvelope = Envelope[User]`.
+//
+// The payload is the value of payloadKey. If it's empty, the payload key is detected by DetectPayloadKey.
+// opts.TypeName is the name of the envelope and defaults to "Envelope". Only Go output is supported, and
+// ValidateMethod isn't.
+func GenerateEnvelope(samples []EnvelopeSample, payloadKey string, opts Options) (*Result, error) {
+	if opts.TypeName == "" {
+		opts.TypeName = defaultEnvelopeName
+	}
+	opts = opts.withDefaults()
+	if err := validateOptions(opts); err != nil {
+		return nil, err
+	}
+	if opts.Format != FormatGo {
+		return nil, fmt.Errorf("envelopes can only be generated as Go, not %v", opts.Format)
+	}
+	if opts.ValidateMethod {
+		return nil, errors.New("envelopes don't support a Validate method")
+	}
+
+	groups, err := groupPayloads(samples)
+	if err != nil {
+		return nil, err
+	}
+	if err := nameGroups(groups, opts.TypeName); err != nil {
+		return nil, err
+	}
+	if payloadKey == "" {
+		payloadKey, err = DetectPayloadKey(samples)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var trees []parse.Node
+	for _, sample := range samples {
+		trees = append(trees, sample.Tree)
+	}
+	root := model.Infer(trees...)
+	if root.Field(payloadKey) == nil {
+		return nil, fmt.Errorf("no sample has the payload key %q", payloadKey)
+	}
+
+	g := &Generator{
+		file: jen.NewFile(opts.PackageName),
+		opts: opts,
+	}
+	generatedFile, err := g.startEnvelope(root, payloadKey, groups)
+	if err != nil {
+		return nil, err
+	}
+	code, err := generateOutput(generatedFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Code: code}, nil
+}
+
+// DetectPayloadKey returns the key holding the payload of the envelope shared by samples. It's the only key whose
+// values differ in shape between samples of different names. If there's no such key, e.g. because there's only a
+// single name, or if there are several, it's the first of them named like payloads usually are, e.g. data.
+func DetectPayloadKey(samples []EnvelopeSample) (string, error) {
+	groups, err := groupPayloads(samples)
+	if err != nil {
+		return "", err
+	}
+
+	var keys, differing []string
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		for _, object := range group.samples {
+			for _, key := range object.OrderedKeys() {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	for _, key := range keys {
+		if differsBetweenGroups(groups, key) {
+			differing = append(differing, key)
+		}
+	}
+	if len(differing) == 1 {
+		return differing[0], nil
+	}
+
+	candidates := differing
+	if len(candidates) == 0 {
+		candidates = keys
+	}
+	for _, conventional := range conventionalPayloadKeys {
+		if containsString(candidates, conventional) {
+			return conventional, nil
+		}
+	}
+	if len(differing) == 0 {
+		return "", errors.New("can't detect the payload key, since no key differs between the samples")
+	}
+	return "", fmt.Errorf("can't detect the payload key, since several keys differ between the samples: %v", strings.Join(differing, ", "))
+}
+
+// groupPayloads groups samples by their name in the order of their first appearance.
+func groupPayloads(samples []EnvelopeSample) ([]*payloadGroup, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to generate types for")
+	}
+
+	var groups []*payloadGroup
+	byName := make(map[string]*payloadGroup)
+	for _, sample := range samples {
+		object, ok := sample.Tree.(*parse.ObjectNode)
+		if !ok {
+			return nil, fmt.Errorf("sample %q isn't an object, so it has no envelope", sample.Name)
+		}
+		if group, ok := byName[sample.Name]; ok {
+			group.samples = append(group.samples, object)
+			continue
+		}
+		group := &payloadGroup{name: sample.Name, samples: []*parse.ObjectNode{object}}
+		byName[sample.Name] = group
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// nameGroups names the payload types of groups after their names. Neither they nor the aliases of the envelopes
+// holding them may collide with envelopeName or each other.
+func nameGroups(groups []*payloadGroup, envelopeName string) error {
+	owners := map[string]string{envelopeName: "the envelope"}
+	for _, group := range groups {
+		group.typeName = makeTypeName(group.name)
+		if !token.IsIdentifier(group.typeName) {
+			return fmt.Errorf("can't derive a type name from the sample name %q", group.name)
+		}
+		for _, name := range []string{group.typeName, group.typeName + envelopeName} {
+			if owner, ok := owners[name]; ok {
+				return fmt.Errorf("the type %v of the sample name %q collides with %v", name, group.name, owner)
+			}
+			owners[name] = fmt.Sprintf("the types of the sample name %q", group.name)
+		}
+	}
+	return nil
+}
+
+// differsBetweenGroups reports whether the values of key differ in shape between groups.
+func differsBetweenGroups(groups []*payloadGroup, key string) bool {
+	var first *model.Type
+	for _, group := range groups {
+		t, ok := group.infer(key)
+		if !ok {
+			continue
+		}
+		if first == nil {
+			first = t
+		} else if !sameShape(first, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// infer returns the type of the values of key in the samples of g. It returns false if none of them has the key.
+func (g *payloadGroup) infer(key string) (*model.Type, bool) {
+	var values []parse.Node
+	for _, object := range g.samples {
+		values = append(values, object.Children[key]...)
+	}
+	if len(values) == 0 {
+		return nil, false
+	}
+	return model.Infer(values...), true
+}
+
+// sameShape reports whether a and b would be generated as the same Go type. Null and values nothing is known about
+// fit any shape, and so do integers and floats each other.
+func sameShape(a, b *model.Type) bool {
+	a, b = a.WithoutNull(), b.WithoutNull()
+	if a.Kind == model.KindNull || a.Kind == model.KindAny || b.Kind == model.KindNull || b.Kind == model.KindAny {
+		return true
+	}
+	if isNumeric(a) && isNumeric(b) {
+		return true
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case model.KindObject:
+		if len(a.Fields) != len(b.Fields) {
+			return false
+		}
+		for _, field := range a.Fields {
+			other := b.Field(field.Key)
+			if other == nil || !sameShape(field.Type, other.Type) {
+				return false
+			}
+		}
+	case model.KindArray:
+		return sameShape(a.Elem, b.Elem)
+	case model.KindUnion:
+		if len(a.Variants) != len(b.Variants) {
+			return false
+		}
+		for _, variant := range a.Variants {
+			other := b.Variant(variant.Kind)
+			if other == nil || !sameShape(variant, other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isNumeric reports whether t is an integer, a float or a union of both.
+func isNumeric(t *model.Type) bool {
+	return t.Kind == model.KindInteger || t.Kind == model.KindFloat || isNumber(t)
+}
+
+// makeTypeName turns name into an exported identifier by capitalizing its words, e.g. `list-orders` into
+// ListOrders.
+func makeTypeName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+func (g *Generator) startEnvelope(root *model.Type, payloadKey string, groups []*payloadGroup) (file *jen.File, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			file = nil
+			err = errors.New(fmt.Sprint(r))
+			return
+		}
+	}()
+
+	var fields []jen.Code
+	for _, field := range orderFields(root, g.opts.FieldOrder) {
+		if field.Key == payloadKey {
+			fields = append(fields, makeId(field.Key).Id(typeParameterName).Add(renderTag(makeTagValues(g.opts, field.Key), field.Key)))
+			continue
+		}
+		fields = append(fields, g.makeField(field))
+	}
+	g.file.Type().Id(g.opts.TypeName).Types(jen.Id(typeParameterName).Any()).Struct(fields...)
+
+	for _, group := range groups {
+		payload, _ := group.infer(payloadKey)
+		argument := jen.Interface()
+		if payload != nil {
+			switch payload = payload.WithoutNull(); payload.Kind {
+			case model.KindObject, model.KindArray, model.KindString, model.KindInteger, model.KindFloat, model.KindBool:
+				g.file.Line()
+				g.file.Type().Id(group.typeName).Add(g.makeType(payload))
+				argument = jen.Id(group.typeName)
+			}
+		}
+		g.file.Line()
+		g.file.Type().Id(group.typeName + g.opts.TypeName).Op("=").Id(g.opts.TypeName).Types(argument)
+	}
+	return g.file, nil
+}
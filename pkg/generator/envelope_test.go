@@ -0,0 +1,184 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+// makeEnvelopeSamples parses samples, which alternate between names and JSON.
+func makeEnvelopeSamples(t *testing.T, samples ...string) []EnvelopeSample {
+	t.Helper()
+	var envelopeSamples []EnvelopeSample
+	for i := 0; i < len(samples); i += 2 {
+		tree, err := parse.ParseFromString(samples[i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelopeSamples = append(envelopeSamples, EnvelopeSample{Name: samples[i], Tree: tree})
+	}
+	return envelopeSamples
+}
+
+func TestDetectPayloadKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "differing key",
+			samples: []string{
+				"user", `{"ok": true, "result": {"id": 1}}`,
+				"orders", `{"ok": true, "result": [{"id": 1}]}`,
+			},
+			want: "result",
+		},
+		{
+			name: "integers and floats don't differ",
+			samples: []string{
+				"user", `{"took": 1, "result": {"id": 1}}`,
+				"orders", `{"took": 1.5, "result": {"total": 1}}`,
+			},
+			want: "result",
+		},
+		{
+			name: "null fits any shape",
+			samples: []string{
+				"user", `{"error": null, "result": {"id": 1}}`,
+				"failure", `{"error": {"code": 404}, "result": {"name": "a"}}`,
+			},
+			want: "result",
+		},
+		{
+			name: "several differing keys",
+			samples: []string{
+				"user", `{"meta": {"page": 1}, "data": {"id": 1}}`,
+				"orders", `{"meta": {"cursor": "a"}, "data": [{"id": 1}]}`,
+			},
+			want: "data",
+		},
+		{
+			name:    "single name",
+			samples: []string{"user", `{"status": "ok", "payload": {"id": 1}}`},
+			want:    "payload",
+		},
+		{
+			name: "ambiguous",
+			samples: []string{
+				"user", `{"meta": {"page": 1}, "user": {"id": 1}}`,
+				"orders", `{"meta": {"cursor": "a"}, "user": [{"id": 1}]}`,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no differing key",
+			samples: []string{"user", `{"status": "ok", "user": {"id": 1}}`},
+			wantErr: true,
+		},
+		{
+			name:    "no object",
+			samples: []string{"users", `[{"id": 1}]`},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DetectPayloadKey(makeEnvelopeSamples(t, test.samples...))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("DetectPayloadKey(): got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("DetectPayloadKey(): got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGenerateEnvelope(t *testing.T) {
+	samples := makeEnvelopeSamples(t,
+		"user", `{"status": "ok", "error": null, "data": {"id": 1, "name": "a"}}`,
+		"list-orders", `{"status": "ok", "error": null, "data": [{"id": 1, "total": 2.5}]}`,
+		"list-orders", `{"status": "error", "error": {"code": 404, "message": "not found"}, "data": null}`,
+		"health", `{"status": "ok", "error": null}`,
+	)
+	result, err := GenerateEnvelope(samples, "", Options{FieldOrder: FieldOrderSource})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `package generated
+
+type Envelope[T any] struct {
+	Status string ` + "`json:\"status\"`" + `
+	Error  struct {
+		Code    int    ` + "`json:\"code\"`" + `
+		Message string ` + "`json:\"message\"`" + `
+	} ` + "`json:\"error\"`" + `
+	Data T ` + "`json:\"data\"`" + `
+}
+
+type User struct {
+	Id   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type UserEnvelope = Envelope[User]
+
+type ListOrders []struct {
+	Id    int     ` + "`json:\"id\"`" + `
+	Total float64 ` + "`json:\"total\"`" + `
+}
+
+type ListOrdersEnvelope = Envelope[ListOrders]
+
+type HealthEnvelope = Envelope[interface{}]
+`
+	if result.Code != expected {
+		t.Errorf("GenerateEnvelope(): \n%v", diff.Diff(result.Code, expected))
+	}
+	typeCheck(t, result.Code)
+}
+
+func TestGenerateEnvelopeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		samples    []string
+		payloadKey string
+		opts       Options
+	}{
+		{
+			name:       "missing payload key",
+			samples:    []string{"user", `{"data": {"id": 1}}`},
+			payloadKey: "result",
+		},
+		{
+			name:    "invalid name",
+			samples: []string{"1", `{"data": {"id": 1}}`},
+		},
+		{
+			name:    "name collides with the envelope",
+			samples: []string{"envelope", `{"data": {"id": 1}}`},
+		},
+		{
+			name: "names collide with each other",
+			samples: []string{
+				"user", `{"data": {"id": 1}}`,
+				"user-envelope", `{"data": [1]}`,
+			},
+		},
+		{
+			name:    "other format",
+			samples: []string{"user", `{"data": {"id": 1}}`},
+			opts:    Options{Format: FormatTypeScript},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := GenerateEnvelope(makeEnvelopeSamples(t, test.samples...), test.payloadKey, test.opts); err == nil {
+				t.Errorf("GenerateEnvelope(): expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+)
+
+// maxExampleLength is the number of characters of an example value after which it's truncated.
+const maxExampleLength = 40
+
+// makeExampleComment returns the comments Options.Examples and Options.ExampleStats add to field, e.g.
+// `e.g. "active"` and `seen 3 times, 33% null`.
+func (g *Generator) makeExampleComment(field *model.Field) []string {
+	var comments []string
+	t := field.Type.WithoutNull()
+	if t.Kind == model.KindArray {
+		t = t.Elem.WithoutNull()
+	}
+	if g.opts.Examples && t.Kind != model.KindNull && len(t.Values) > 0 {
+		comments = append(comments, "e.g. "+truncateExample(t.Values[0]))
+	}
+	if g.opts.ExampleStats {
+		comments = append(comments, makeExampleStats(field.Type))
+	}
+	return comments
+}
+
+// makeExampleStats describes how often t was observed and which share of its values were null.
+func makeExampleStats(t *model.Type) string {
+	stats := "seen once"
+	if t.Count != 1 {
+		stats = fmt.Sprintf("seen %d times", t.Count)
+	}
+	if null := t.Variant(model.KindNull); null != nil || t.Kind == model.KindNull {
+		nullCount := t.Count
+		if null != nil {
+			nullCount = null.Count
+		}
+		stats += fmt.Sprintf(", %d%% null", nullCount*100/t.Count)
+	}
+	return stats
+}
+
+// truncateExample shortens literal to maxExampleLength characters. Strings stay quoted.
+func truncateExample(literal string) string {
+	if len([]rune(literal)) <= maxExampleLength {
+		return literal
+	}
+	if unquoted, err := strconv.Unquote(literal); err == nil {
+		return strconv.Quote(string([]rune(unquoted)[:maxExampleLength-2]) + "...")
+	}
+	return string([]rune(literal)[:maxExampleLength]) + "..."
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+)
+
+func TestExampleFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "examples"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			opts := Options{FieldOrder: FieldOrderSource, Examples: true, ExampleStats: true}
+			result, err := GenerateFromString(readFile(filename), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+		})
+	}
+}
+
+func TestTruncateExample(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    string
+	}{
+		{literal: `"short"`, want: `"short"`},
+		{literal: `"` + "ääääääääääääääääääääääääääääääääääääääää" + `"`, want: `"` + "ääääääääääääääääääääääääääääääääääääää" + `..."`},
+		{literal: "1234567890123456789012345678901234567890123", want: "1234567890123456789012345678901234567890..."},
+	}
+	for _, test := range tests {
+		if got := truncateExample(test.literal); got != test.want {
+			t.Errorf("truncateExample(%v): got %v, want %v", test.literal, got, test.want)
+		}
+	}
+}
@@ -1,31 +1,169 @@
+// Package generator generates Go type definitions for the tree built by package parse. The tree is first turned
+// into the language-neutral model of package model, which backends translate into Go, TypeScript,
+// Protocol Buffers or JSON Schema.
+//
+// Generate is the entry point for embedding json2struct. Its Options expose everything the CLI can do.
 package generator
 
 import (
 	"bytes"
 	"errors"
 	"fmt"
+	"go/token"
 	"sort"
+	"strconv"
+	"strings"
 	"unicode"
 
+	"github.com/marhaupe/json2struct/pkg/internal/jsontag"
+	"github.com/marhaupe/json2struct/pkg/model"
 	"github.com/marhaupe/json2struct/pkg/parse"
 
 	"github.com/dave/jennifer/jen"
 )
 
+// Result is the output of Generate.
+type Result struct {
+	// Code is the generated Go source file.
+	Code string
+	// Warnings lists recoverable problems found while parsing the input, e.g. duplicate keys. It's only filled by
+	// GenerateFromString, GenerateFromStringWithParseOptions and GenerateFromOpenAPI, which parse the input
+	// themselves. The others take parsed trees, whose warnings are reported by parse.Options.Warn.
+	Warnings []string
+}
+
+// Generate generates the Go type definitions for tree.
+func Generate(tree parse.Node, opts Options) (*Result, error) {
+	return GenerateFromSamples([]parse.Node{tree}, opts)
+}
+
+// GenerateFromSamples generates the Go type definitions for several samples of the same data, e.g. responses of
+// the same endpoint. Keys that are missing from some of the samples become optional.
+func GenerateFromSamples(samples []parse.Node, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to generate types for")
+	}
+	if err := validateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case FormatJSONSchema:
+		return generateJSONSchema(model.Infer(samples...), opts)
+	case FormatTypeScript:
+		return generateTypeScript(model.Infer(samples...), opts)
+	case FormatProto:
+		return generateProto(model.Infer(samples...), opts)
+	}
+
+	g := &Generator{
+		file: jen.NewFile(opts.PackageName),
+		opts: opts,
+	}
+	generatedFile, err := g.start(inferGoType(samples))
+	if err != nil {
+		return nil, err
+	}
+	code, err := generateOutput(generatedFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Code: code}, nil
+}
+
+// validateOptions checks the names, tags and type overrides of opts, and that all options it sets are supported
+// by its format.
+func validateOptions(opts Options) error {
+	if !token.IsIdentifier(opts.TypeName) {
+		return fmt.Errorf("invalid type name %q", opts.TypeName)
+	}
+	if !token.IsIdentifier(opts.PackageName) {
+		return fmt.Errorf("invalid package name %q", opts.PackageName)
+	}
+	if err := validateTags(opts.Tags); err != nil {
+		return err
+	}
+	if opts.Format != FormatGo {
+		unsupported := sampleOnlyOptions(opts)
+		if len(opts.Tags) > 0 {
+			unsupported = append([]string{"tags"}, unsupported...)
+		}
+		if len(unsupported) > 0 {
+			return fmt.Errorf("%v output doesn't support %v", opts.Format, strings.Join(unsupported, ", "))
+		}
+	}
+	return validateTypeOverrides(opts.TypeOverrides)
+}
+
+// sampleOnlyOptions returns the options set in opts that are only supported by Go types inferred from samples,
+// since they depend on the values observed in them.
+func sampleOnlyOptions(opts Options) []string {
+	var options []string
+	if len(opts.TypeOverrides) > 0 {
+		options = append(options, "type overrides")
+	}
+	if opts.ValidateTags {
+		options = append(options, "validate tags")
+	}
+	if opts.ValidateMethod {
+		options = append(options, "a Validate method")
+	}
+	if opts.Examples {
+		options = append(options, "examples")
+	}
+	if opts.ExampleStats {
+		options = append(options, "example stats")
+	}
+	return options
+}
+
+// GenerateFromString parses s as JSON and generates the Go type definitions for it.
+func GenerateFromString(s string, opts Options) (*Result, error) {
+	return GenerateFromStringWithParseOptions(s, parse.Options{}, opts)
+}
+
+// GenerateFromStringWithParseOptions parses s using parseOpts and generates the Go type definitions for it.
+// The parser keeps the values of s if opts needs them.
+func GenerateFromStringWithParseOptions(s string, parseOpts parse.Options, opts Options) (*Result, error) {
+	var warnings []string
+	warn := parseOpts.Warn
+	parseOpts.KeepValues = parseOpts.KeepValues || opts.needsValues()
+	parseOpts.Warn = func(msg string) {
+		warnings = append(warnings, msg)
+		if warn != nil {
+			warn(msg)
+		}
+	}
+
+	tree, err := parse.ParseFromStringWithOptions(s, parseOpts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := Generate(tree, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = warnings
+	return result, nil
+}
+
+// GenerateOutputFromString is a shorthand for GenerateFromString using the default Options.
 func GenerateOutputFromString(s string) (string, error) {
-	generatedFile, err := generateFileFromString(s)
+	result, err := GenerateFromString(s, Options{})
 	if err != nil {
 		return "", err
 	}
-	return generateOutput(generatedFile)
+	return result.Code, nil
 }
 
+// GenerateOutputFromAST is a shorthand for Generate using the default Options.
 func GenerateOutputFromAST(tree parse.Node) (string, error) {
-	generatedFile, err := generateFileFromAST(tree)
+	result, err := Generate(tree, Options{})
 	if err != nil {
 		return "", err
 	}
-	return generateOutput(generatedFile)
+	return result.Code, nil
 }
 
 func generateOutput(generatedFile *jen.File) (string, error) {
@@ -37,31 +175,17 @@ func generateOutput(generatedFile *jen.File) (string, error) {
 	return buf.String(), nil
 }
 
-func generateFileFromString(s string) (*jen.File, error) {
-	node, err := parse.ParseFromString(s)
-	if err != nil {
-		return nil, err
-	}
-	return generateFileFromAST(node)
-}
-
-func generateFileFromAST(tree parse.Node) (*jen.File, error) {
-	g := Generator{
-		Tree:        tree,
-		currentNode: tree,
-		file:        jen.NewFile("generated"),
-	}
-	return g.start()
-}
-
+// Generator holds the state of a single run of Generate. It's the Go backend, which translates the model
+// inferred from the input into Go type definitions.
 type Generator struct {
-	Tree        parse.Node
-	currentNode parse.Node
-
 	file *jen.File
+	opts Options
+	// canBeZero reports whether the struct whose fields are being generated may be the zero value, see
+	// canBeZeroStruct.
+	canBeZero bool
 }
 
-func (g Generator) start() (file *jen.File, err error) {
+func (g *Generator) start(root *model.Type) (file *jen.File, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			file = nil
@@ -70,157 +194,198 @@ func (g Generator) start() (file *jen.File, err error) {
 		}
 	}()
 
-	rootStmt := g.file.Type().Id("JSONToStruct")
+	rootStmt := g.file.Type().Id(g.opts.TypeName)
 
-	switch g.Tree.Type() {
-	case parse.NodeTypeArray:
-		casted := g.Tree.(*parse.ArrayNode)
-		rootStmt.Add(makeArray(casted))
-	case parse.NodeTypeObject:
-		casted := g.Tree.(*parse.ObjectNode)
-		rootStmt.Add(makeStruct(casted))
+	switch root.Kind {
+	case model.KindArray:
+		rootStmt.Add(g.makeArray(root))
+	case model.KindObject:
+		rootStmt.Add(g.makeStruct(root))
 	default:
 		panic("invalid json. expected { or [ as initial node but received something else")
 	}
 
+	if g.opts.ValidateMethod {
+		g.file.Line()
+		g.file.Add(g.makeValidateMethod(root))
+	}
 	return g.file, nil
 }
 
-func makeArray(arr *parse.ArrayNode) *jen.Statement {
-	// 	Many different datatypes e.g. strings and objects,
-	// 	or no datatypes at all (empty array)
-	//	-> The generated code is []interface{}
-	childrenTypeCount := countNodeTypes(arr.Children)
-	if childrenTypeCount != 1 {
-		return jen.Index().Interface()
+// makeType returns the Go type for t. Values that were observed with different kinds, or with no kind at all,
+// become interface{}.
+func (g *Generator) makeType(t *model.Type) *jen.Statement {
+	switch t = t.WithoutNull(); t.Kind {
+	case model.KindObject:
+		return g.makeStruct(t)
+	case model.KindArray:
+		return g.makeArray(t)
+	case model.KindString, model.KindInteger, model.KindFloat, model.KindBool:
+		return makePrimTypedef(t.Kind)
+	default:
+		return jen.Interface()
 	}
+}
 
-	// 	Only arrays as children
+func (g *Generator) makeArray(arr *model.Type) *jen.Statement {
+	switch elem := arr.Elem.WithoutNull(); elem.Kind {
+	// 	Only structs as children, which have been merged into one
+	//	-> The generated code is []struct{...}
+	case model.KindObject:
+		// The elements are zero structs only if they were null.
+		defer func(canBeZero bool) { g.canBeZero = canBeZero }(g.canBeZero)
+		g.canBeZero = arr.Elem.Nullable()
+		return jen.Index().Add(g.makeStruct(elem))
+
+	// 	Only one primitive datatype e.g. only strings
+	//	-> The generated code is []string
+	case model.KindString, model.KindInteger, model.KindFloat, model.KindBool:
+		return jen.Index().Add(makePrimTypedef(elem.Kind))
+
+	// 	Many different datatypes e.g. strings and objects, only arrays as children,
+	// 	or no datatypes at all (empty array)
 	//	-> The generated code is []interface{}
-	if arr.Children[0].Type() == parse.NodeTypeArray {
+	default:
 		return jen.Index().Interface()
 	}
+}
 
-	// 	Only structs as children
-	//	-> We have to merge the structs since we don't
-	// 			want to "lose" data when ultimately parsing with
-	//			the generated code.
-	if arr.Children[0].Type() == parse.NodeTypeObject {
-
-		// Only merge the children objects if the size is greater than one.
-		if len(arr.Children) > 1 {
-			mergedObject := mergeObjects(castToObjectArr(arr.Children))
-			return jen.Index().Add(makeStruct(mergedObject))
-		}
+func (g *Generator) makeStruct(obj *model.Type) *jen.Statement {
+	var children []jen.Code
+	for _, field := range orderFields(obj, g.opts.FieldOrder) {
+		children = append(children, g.makeField(field))
+	}
+	return jen.Struct(children...)
+}
 
-		// At this point, we are sure that there is only one object as a child
-		return jen.Index().Add(makeStruct(arr.Children[0].(*parse.ObjectNode)))
+// makeField returns the declaration of field within a struct, e.g. a field `Title string` with its tags.
+func (g *Generator) makeField(field *model.Field) *jen.Statement {
+	tags := makeTagValues(g.opts, field.Key)
+	if validate := makeValidateTagValue(field, g.canBeZero); g.opts.ValidateTags && validate != "" && !g.isOverridden(field) {
+		tags["validate"] = validate
 	}
+	defer func(canBeZero bool) { g.canBeZero = canBeZero }(g.canBeZero)
+	g.canBeZero = g.canBeZero || canBeZeroStruct(field)
+	return makeId(field.Key).
+		Add(g.makeFieldType(field)).
+		Add(renderTag(tags, field.Key, g.makeExampleComment(field)...))
+}
 
-	// 	Only one primitive datatype e.g. only strings
-	//	-> The generated code is []string
-	return jen.Index().Add(makePrimTypedef(arr.Children[0].Type()))
+// inferGoType returns the type of samples that Go types are generated from. The values of a key are those of the
+// first object with that key, unless they are objects, which are merged, and null counts as a kind of its own.
+// The same goes for several samples, which are merged like the elements of an array. Everything else, e.g. how
+// often a value was observed, is inferred from all values.
+func inferGoType(samples []parse.Node) *model.Type {
+	merged := mergeFirstValues(mergeSamples(samples))
+	return withFirstValues(model.Infer(samples...), model.Infer(merged))
 }
 
-func makeStruct(obj *parse.ObjectNode) *jen.Statement {
-	var children []jen.Code
+// mergeSamples returns a single node for samples of the same kind. Objects are merged like the objects of an
+// array, and the elements of arrays are joined. Samples of different kinds are represented by the first one.
+func mergeSamples(samples []parse.Node) parse.Node {
+	if len(samples) == 1 || countNodeTypes(samples) != 1 {
+		return samples[0]
+	}
+	switch samples[0].Type() {
+	case parse.NodeTypeObject:
+		return mergeObjects(castToObjectArr(samples))
+	case parse.NodeTypeArray:
+		merged := &parse.ArrayNode{NodeType: parse.NodeTypeArray}
+		for _, sample := range samples {
+			merged.Children = append(merged.Children, sample.(*parse.ArrayNode).Children...)
+		}
+		return merged
+	default:
+		return samples[0]
+	}
+}
 
-	var sortedVarnames []string
-	for varname := range obj.Children {
-		sortedVarnames = append(sortedVarnames, varname)
+// withFirstValues returns full, which was inferred from all values, with the kinds of first, which was only
+// inferred from the values of mergeFirstValues. Values that were null in first become interface{}.
+func withFirstValues(full, first *model.Type) *model.Type {
+	if first.Nullable() {
+		return withoutKnownKinds(full)
+	}
+	if full.Kind != first.Kind {
+		variant := full.Variant(first.Kind)
+		if variant == nil {
+			return first
+		}
+		full = variant
 	}
-	sort.Strings(sortedVarnames)
 
-	for _, varname := range sortedVarnames {
+	t := *full
+	t.Fields = nil
+	for _, field := range first.Fields {
+		if fullField := full.Field(field.Key); fullField != nil {
+			merged := *fullField
+			merged.Type = withFirstValues(fullField.Type, field.Type)
+			field = &merged
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	if first.Elem != nil && full.Elem != nil {
+		t.Elem = withFirstValues(full.Elem, first.Elem)
+	}
+	return &t
+}
 
-		valueArray := obj.Children[varname]
-		childrenWithSharedKey := len(valueArray)
-		typeCount := countNodeTypes(valueArray)
+// withoutKnownKinds returns t, which is null or has a null variant, with its other variants replaced by a single
+// one nothing is known about, which makes it interface{}.
+func withoutKnownKinds(t *model.Type) *model.Type {
+	null := t.Variant(model.KindNull)
+	if null == nil {
+		return t
+	}
+	return &model.Type{
+		Kind:     model.KindUnion,
+		Variants: []*model.Type{{Kind: model.KindAny, Count: t.Count - null.Count}, null},
+		Count:    t.Count,
+	}
+}
 
-		if childrenWithSharedKey == 0 {
-			continue
+// mergeFirstValues returns node with the objects of every array merged into a single object, and so are several
+// objects of the same key. For every key, the merged object keeps the values of the first object with that key,
+// unless they are objects as well, which are merged recursively.
+func mergeFirstValues(node parse.Node) parse.Node {
+	switch node := node.(type) {
+	case *parse.ArrayNode:
+		children := node.Children
+		if len(children) > 1 && countNodeTypes(children) == 1 && children[0].Type() == parse.NodeTypeObject {
+			children = []parse.Node{mergeObjects(castToObjectArr(children))}
 		}
-
-		if childrenWithSharedKey == 1 {
-			switch valueArray[0].Type() {
-			case parse.NodeTypeArray:
-				childArr := valueArray[0].(*parse.ArrayNode)
-				children = append(
-					children,
-					makeId(varname).
-						Add(makeArray(childArr)).
-						Add(makeJSONTag(varname)),
-				)
-			case parse.NodeTypeObject:
-				childObj := valueArray[0].(*parse.ObjectNode)
-				children = append(
-					children,
-					makeId(varname).
-						Add(makeStruct(childObj)).
-						Add(makeJSONTag(varname)),
-				)
-			default:
-				children = append(
-					children,
-					makeId(varname).
-						Add(makePrimTypedef(valueArray[0].Type())).
-						Add(makeJSONTag(varname)),
-				)
+		merged := &parse.ArrayNode{NodeType: node.NodeType}
+		for _, child := range children {
+			merged.Children = append(merged.Children, mergeFirstValues(child))
+		}
+		return merged
+	case *parse.ObjectNode:
+		merged := &parse.ObjectNode{NodeType: node.NodeType, Children: make(map[string][]parse.Node), Keys: node.Keys}
+		for key, valueArray := range node.Children {
+			if len(valueArray) > 1 && countNodeTypes(valueArray) == 1 && valueArray[0].Type() == parse.NodeTypeObject {
+				valueArray = []parse.Node{mergeObjects(castToObjectArr(valueArray))}
 			}
-
-		} else if childrenWithSharedKey > 1 {
-
-			if typeCount > 1 {
-				children = append(
-					children,
-					makeId(varname).
-						Add(jen.Interface()).
-						Add(makeJSONTag(varname)),
-				)
-
-				// If there are different objects for the same key, we should merge them together.
-			} else if typeCount == 1 {
-
-				switch typ := valueArray[0].Type(); typ {
-				case parse.NodeTypeObject:
-					compositeObj := mergeObjects(castToObjectArr(valueArray))
-					children = append(children,
-						makeId(varname).
-							Add(makeStruct(compositeObj)).
-							Add(makeJSONTag(varname)),
-					)
-				case parse.NodeTypeBool:
-					fallthrough
-				case parse.NodeTypeFloat:
-					fallthrough
-				case parse.NodeTypeString:
-					fallthrough
-				case parse.NodeTypeInteger:
-					children = append(children,
-						makeId(varname).
-							Add(makePrimTypedef(typ)).
-							Add(makeJSONTag(varname)),
-					)
-				default:
-					children = append(
-						children,
-						makeId(varname).
-							Add(jen.Interface()).
-							Add(makeJSONTag(varname)),
-					)
-
-				}
+			for _, value := range valueArray {
+				merged.Children[key] = append(merged.Children[key], mergeFirstValues(value))
 			}
 		}
+		return merged
+	default:
+		return node
 	}
-
-	return jen.Struct(children...)
 }
 
 func mergeObjects(children []*parse.ObjectNode) *parse.ObjectNode {
 	mergedChildren := make(map[string][]parse.Node)
+	var mergedKeys []string
 
 	for _, object := range children {
+		for _, varname := range object.OrderedKeys() {
+			if _, ok := mergedChildren[varname]; !ok {
+				mergedKeys = append(mergedKeys, varname)
+				mergedChildren[varname] = nil
+			}
+		}
 		for varname, valueArray := range object.Children {
 			if mergedChildren[varname] == nil {
 				mergedChildren[varname] = valueArray
@@ -249,6 +414,84 @@ func mergeObjects(children []*parse.ObjectNode) *parse.ObjectNode {
 	return &parse.ObjectNode{
 		NodeType: parse.NodeTypeObject,
 		Children: mergedChildren,
+		Keys:     mergedKeys,
+	}
+}
+
+func castToObjectArr(arr []parse.Node) []*parse.ObjectNode {
+	objectArr := make([]*parse.ObjectNode, 0, len(arr))
+	for _, child := range arr {
+		obj, ok := child.(*parse.ObjectNode)
+		if !ok {
+			panic("casting a node to objectnode failed")
+		}
+		objectArr = append(objectArr, obj)
+	}
+	return objectArr
+}
+
+func countNodeTypes(children []parse.Node) int {
+	// If there are only zero or one children, then there are zero or one
+	// different types of children aswell.
+	childrenCount := len(children)
+	if childrenCount <= 1 {
+		return childrenCount
+	}
+
+	foundTypes := make(map[parse.NodeType]bool, 0)
+	for _, child := range children {
+		foundTypes[child.Type()] = true
+	}
+	return len(foundTypes)
+}
+
+// orderFields returns the fields of obj in the order they should be generated in.
+func orderFields(obj *model.Type, order FieldOrder) []*model.Field {
+	if order == FieldOrderSource {
+		return obj.Fields
+	}
+
+	sortedFields := make([]*model.Field, len(obj.Fields))
+	copy(sortedFields, obj.Fields)
+	sort.Slice(sortedFields, func(i, j int) bool {
+		return sortedFields[i].Key < sortedFields[j].Key
+	})
+	return sortedFields
+}
+
+// TypeNameForPath returns a name for the root type of the values selected by p, derived from its last key, e.g.
+// `Items` for `data.items`, and `Item` for its elements selected by `data.items[*]`. It returns "" if there's
+// no key to derive a name from.
+func TypeNameForPath(p parse.Path) string {
+	for i := len(p) - 1; i >= 0; i-- {
+		// Tokens of JSON Pointers like `0` are most likely indices.
+		if p[i].IsIndex || p[i].Wildcard || p[i].Key == strconv.Itoa(p[i].Index) {
+			continue
+		}
+		name := makeVarname(p[i].Key)
+		if i < len(p)-1 {
+			name = singularize(name)
+		}
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return ""
+		}
+		return name
+	}
+	return ""
+}
+
+// singularize returns the singular of an English plural, e.g. `Item` for `Items` or `Category` for
+// `Categories`. Other words are returned as they are.
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && len(word) > 1:
+		return strings.TrimSuffix(word, "s")
+	default:
+		return word
 	}
 }
 
@@ -285,6 +528,9 @@ func upperCaseFirstLetter(characters []rune) []rune {
 // unicode_letter = /* a Unicode code point classified as "Letter" */ .
 // unicode_digit  = /* a Unicode code point classified as "Number, decimal digit" */ .
 func stripInvalidCharacters(characters []rune) []rune {
+	if len(characters) == 0 {
+		return []rune{'_'}
+	}
 	cleanedCharacters := make([]rune, len(characters))
 	copy(cleanedCharacters, characters)
 
@@ -317,52 +563,47 @@ func identifierIsPredeclared(identifier string) bool {
 	return ok
 }
 
-// addJSONTag adds the json-tag, e.g. `json:"title"`. This has to match the original varname from the json file
-func makeJSONTag(varname string) *jen.Statement {
-	return jen.Tag(map[string]string{"json": varname})
+// makeTag adds the json-tag, e.g. `json:"title"` or `json:"title,omitempty"`, followed by the further tags of
+// opts.Tags. The json-tag has to match the original varname from the json file.
+// Quotes and backslashes in the varname are escaped by jen. encoding/json however ignores tag names with characters
+// like quotes or commas, so we point out that such a key can't be matched.
+func makeTag(opts Options, varname string, options ...string) *jen.Statement {
+	return renderTag(makeTagValues(opts, varname, options...), varname)
 }
 
-// Depending on `typ`, add the type of the identifier, e.g. `Title string`.
-func makePrimTypedef(typ parse.NodeType) *jen.Statement {
-	switch typ {
-	case parse.NodeTypeBool:
-		return jen.Bool()
-	case parse.NodeTypeNil:
-		return jen.Interface()
-	case parse.NodeTypeString:
-		return jen.String()
-	case parse.NodeTypeInteger:
-		return jen.Int()
-	case parse.NodeTypeFloat:
-		return jen.Float64()
-	default:
-		panic("received unexpected primitive nodetype")
+// makeTagValues returns the values of the tags makeTag generates, keyed by their names.
+func makeTagValues(opts Options, varname string, options ...string) map[string]string {
+	tags := map[string]string{"json": tagValue(Tag{Name: "json"}, varname, options)}
+	for _, tag := range opts.Tags {
+		tags[tag.Name] = tagValue(tag, varname, options)
 	}
+	return tags
 }
 
-func castToObjectArr(arr []parse.Node) []*parse.ObjectNode {
-	objectArr := make([]*parse.ObjectNode, 0, len(arr))
-	for _, child := range arr {
-		obj, ok := child.(*parse.ObjectNode)
-		if !ok {
-			panic("casting a node to objectnode failed")
-		}
-		objectArr = append(objectArr, obj)
+// renderTag renders tags followed by a line comment consisting of comments, if any.
+func renderTag(tags map[string]string, varname string, comments ...string) *jen.Statement {
+	tag := jen.Tag(tags)
+	if !jsontag.IsValidName(varname) {
+		comments = append([]string{fmt.Sprintf("encoding/json can't match the key %q with this tag", varname)}, comments...)
 	}
-	return objectArr
-}
-
-func countNodeTypes(children []parse.Node) int {
-	// If there are only zero or one children, then there are zero or one
-	// different types of children aswell.
-	childrenCount := len(children)
-	if childrenCount <= 1 {
-		return childrenCount
+	if len(comments) > 0 {
+		tag.Comment(strings.Join(comments, "; "))
 	}
+	return tag
+}
 
-	foundTypes := make(map[parse.NodeType]bool, 0)
-	for _, child := range children {
-		foundTypes[child.Type()] = true
+// Depending on `kind`, add the type of the identifier, e.g. `Title string`.
+func makePrimTypedef(kind model.Kind) *jen.Statement {
+	switch kind {
+	case model.KindBool:
+		return jen.Bool()
+	case model.KindString:
+		return jen.String()
+	case model.KindInteger:
+		return jen.Int()
+	case model.KindFloat:
+		return jen.Float64()
+	default:
+		panic("received unexpected primitive kind")
 	}
-	return len(foundTypes)
 }
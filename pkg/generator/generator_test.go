@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/jsonschema"
+	"github.com/marhaupe/json2struct/pkg/openapi"
+	"github.com/marhaupe/json2struct/pkg/parse"
 )
 
 func Test_identifierIsValid(t *testing.T) {
@@ -74,6 +77,16 @@ func Test_identifierIsValid(t *testing.T) {
 			args:                    args{"snake_case"},
 			wantedCleanedIdentifier: "Snake_case",
 		},
+		{
+			name:                    "decoded unicode",
+			args:                    args{"café"},
+			wantedCleanedIdentifier: "Café",
+		},
+		{
+			name:                    "empty",
+			args:                    args{""},
+			wantedCleanedIdentifier: "_",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,7 +101,7 @@ const dirName = "testdata"
 const expectedSuffix = "_expected"
 
 func TestFiles(t *testing.T) {
-	inputFiles, err := listValidInputFiles()
+	inputFiles, err := listValidInputFiles(dirName)
 	if err != nil {
 		t.Fatal("Error reading input files", err)
 	}
@@ -114,6 +127,189 @@ func TestFiles(t *testing.T) {
 	}
 }
 
+func TestFieldOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		fieldOrder   FieldOrder
+		expectedFile string
+	}{
+		{
+			name:         "alphabetical",
+			fieldOrder:   FieldOrderAlphabetical,
+			expectedFile: "alpha" + expectedSuffix,
+		},
+		{
+			name:         "source",
+			fieldOrder:   FieldOrderSource,
+			expectedFile: "source" + expectedSuffix,
+		},
+	}
+	input := readFile(path.Join(dirName, "field_order", "input"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := parse.ParseFromString(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Generate(tree, Options{FieldOrder: tt.fieldOrder})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(path.Join(dirName, "field_order", tt.expectedFile))
+			if actual := result.Code; actual != expected {
+				t.Errorf("Test failed. \nDiff: \n\n%v", diff.Diff(actual, expected))
+			}
+		})
+	}
+}
+
+func TestGenerateFromString(t *testing.T) {
+	parseOpts := parse.Options{Lenient: true, DuplicateKeys: parse.DuplicateKeysLastWins}
+	result, err := GenerateFromStringWithParseOptions(`{ id: 1, "id": 2 }`, parseOpts, Options{
+		PackageName: "api",
+		TypeName:    "User",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "package api\n\ntype User struct {\n\tId int `json:\"id\"`\n}\n"
+	if result.Code != expected {
+		t.Errorf("Test failed. \nDiff: \n\n%v", diff.Diff(result.Code, expected))
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("expected a warning for the duplicate key, got %v", result.Warnings)
+	}
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+	tree, err := parse.ParseFromString(`{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []Options{{TypeName: "my-type"}, {PackageName: "1st"}} {
+		if _, err := Generate(tree, opts); err == nil {
+			t.Errorf("expected an error for options %+v", opts)
+		}
+	}
+}
+
+func TestGenerateUnsupportedOptions(t *testing.T) {
+	tree, err := parse.ParseFromString(`{ "id": 1 }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := jsonschema.Parse([]byte(`{ "properties": { "id": { "type": "integer" } } }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Parse([]byte(`{ "openapi": "3.0.0", "components": { "schemas": { "User": { "type": "object" } } } }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsupportedByOtherFormats := []Options{
+		{Tags: []Tag{{Name: "db"}}},
+		{TypeOverrides: map[string]string{"id": "string"}},
+		{ValidateTags: true},
+		{ValidateMethod: true},
+		{Examples: true},
+		{ExampleStats: true},
+	}
+	for _, format := range []Format{FormatJSONSchema, FormatTypeScript, FormatProto} {
+		for _, opts := range unsupportedByOtherFormats {
+			opts.Format = format
+			if _, err := Generate(tree, opts); err == nil {
+				t.Errorf("Generate(): expected an error for options %+v", opts)
+			}
+		}
+	}
+	for _, opts := range unsupportedByOtherFormats[1:] {
+		if _, err := GenerateFromSchema(schema, opts); err == nil {
+			t.Errorf("GenerateFromSchema(): expected an error for options %+v", opts)
+		}
+		if _, err := GenerateFromOpenAPI(doc, parse.Options{}, opts); err == nil {
+			t.Errorf("GenerateFromOpenAPI(): expected an error for options %+v", opts)
+		}
+	}
+	if _, err := GenerateFromSchema(schema, unsupportedByOtherFormats[0]); err != nil {
+		t.Errorf("GenerateFromSchema(): got error %v for tags", err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	tree, err := parse.ParseYAML("name: web\nports:\n  - 80\n", parse.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Generate(tree, Options{Tags: []Tag{{Name: "yaml"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "package generated\n\ntype JSONToStruct struct {\n" +
+		"\tName  string `json:\"name\" yaml:\"name\"`\n" +
+		"\tPorts []int  `json:\"ports\" yaml:\"ports\"`\n" +
+		"}\n"
+	if result.Code != expected {
+		t.Errorf("Test failed. \nDiff: \n\n%v", diff.Diff(result.Code, expected))
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "keys of later elements",
+			input:    `[{"a": 1}, {"b": "x"}]`,
+			expected: "A int    `json:\"a\"`\n\tB string `json:\"b\"`",
+		},
+		{
+			name:     "kinds of later elements",
+			input:    `[{"a": 1}, {"a": "x"}]`,
+			expected: "A int `json:\"a\"`",
+		},
+		{
+			name:     "arrays of later elements",
+			input:    `[{"a": []}, {"a": [1]}]`,
+			expected: "A []interface{} `json:\"a\"`",
+		},
+		{
+			name:     "objects of later elements",
+			input:    `[{"a": {"b": 1}}, {"a": {"c": true}}]`,
+			expected: "A struct {\n\t\tB int  `json:\"b\"`\n\t\tC bool `json:\"c\"`\n\t} `json:\"a\"`",
+		},
+		{
+			name:     "null and a single kind",
+			input:    `[{"a": null}, {"a": 1.5}]`,
+			expected: "A interface{} `json:\"a\"`",
+		},
+		{
+			name:     "null of later elements",
+			input:    `[{"a": 1.5}, {"a": null}]`,
+			expected: "A float64 `json:\"a\"`",
+		},
+		{
+			name:     "null and objects",
+			input:    `[{"a": null}, {"a": {"b": true}}]`,
+			expected: "A interface{} `json:\"a\"`",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := GenerateOutputFromString(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := "package generated\n\ntype JSONToStruct []struct {\n\t" + test.expected + "\n}\n"
+			if actual != expected {
+				t.Errorf("Test failed. \nDiff: \n\n%v", diff.Diff(actual, expected))
+			}
+		})
+	}
+}
+
 func BenchmarkLargeFile(b *testing.B) {
 	largeFile := readFile("./testdata/big_reddit_response")
 
@@ -125,7 +321,7 @@ func BenchmarkLargeFile(b *testing.B) {
 	}
 }
 
-func listValidInputFiles() ([]string, error) {
+func listValidInputFiles(dirName string) ([]string, error) {
 	dirFiles, err := ioutil.ReadDir(dirName)
 	if err != nil {
 		return nil, err
@@ -136,6 +332,9 @@ func listValidInputFiles() ([]string, error) {
 
 	for _, f := range dirFiles {
 		fileName := f.Name()
+		if f.IsDir() {
+			continue
+		}
 		isExpectedFile := strings.HasSuffix(fileName, expectedSuffix)
 		if isExpectedFile {
 			inputFileNameLength := strings.Index(fileName, expectedSuffix)
@@ -162,3 +361,31 @@ func readFile(filename string) string {
 	}
 	return string(content)
 }
+
+func TestTypeNameForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "", want: ""},
+		{path: "data.user", want: "User"},
+		{path: "data.items", want: "Items"},
+		{path: "data.items[*]", want: "Item"},
+		{path: "/data/categories/0", want: "Category"},
+		{path: "data.categories[0]", want: "Category"},
+		{path: "addresses[*]", want: "Address"},
+		{path: "status[*]", want: "Status"},
+		{path: "data.items[*].tags[*]", want: "Tag"},
+		{path: "[*]", want: ""},
+		{path: "data['2fa']", want: ""},
+	}
+	for _, test := range tests {
+		p, err := parse.ParsePath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := TypeNameForPath(p); got != test.want {
+			t.Errorf("TypeNameForPath(%q): got %q, want %q", test.path, got, test.want)
+		}
+	}
+}
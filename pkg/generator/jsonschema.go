@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/marhaupe/json2struct/pkg/jsonschema"
+	"github.com/marhaupe/json2struct/pkg/model"
+)
+
+// generateJSONSchema renders the schema describing root. The root type name becomes the title of the schema.
+func generateJSONSchema(root *model.Type, opts Options) (*Result, error) {
+	if root.Kind != model.KindObject && root.Kind != model.KindArray {
+		return nil, fmt.Errorf("invalid json. expected { or [ as initial node but received something else")
+	}
+
+	schema := jsonschema.FromModel(root)
+	schema.Schema = jsonschema.Draft202012
+	schema.Title = opts.TypeName
+	if opts.FieldOrder != FieldOrderSource {
+		schema.SortProperties()
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(schema); err != nil {
+		return nil, fmt.Errorf("error rendering schema: %v", err)
+	}
+	return &Result{Code: buf.String()}, nil
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/marhaupe/json2struct/pkg/openapi"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+// GenerateFromOpenAPI generates Go type definitions for an OpenAPI 3 document: a named type for every schema
+// in `components/schemas`, just like GenerateFromSchema does for `$defs`, followed by a type for the example
+// payloads of every request and response body, just like Generate does for samples. Several examples of the
+// same body are merged into one type, and examples that aren't objects or arrays are skipped with a warning.
+// The examples are parsed using parseOpts.
+func GenerateFromOpenAPI(doc *openapi.Document, parseOpts parse.Options, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	if opts.Format != FormatGo {
+		return nil, fmt.Errorf("generating %v from an OpenAPI document isn't supported", opts.Format)
+	}
+	if err := validateOptions(opts); err != nil {
+		return nil, err
+	}
+	if unsupported := sampleOnlyOptions(opts); len(unsupported) > 0 {
+		return nil, fmt.Errorf("types generated from an OpenAPI document don't support %v", strings.Join(unsupported, ", "))
+	}
+
+	file := jen.NewFile(opts.PackageName)
+	sg := &schemaGenerator{
+		doc:       doc.Schemas,
+		file:      file,
+		opts:      opts,
+		typeNames: make(map[string]string),
+		usedNames: make(map[string]bool),
+	}
+	if _, err := sg.start(); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	warn := parseOpts.Warn
+	parseOpts.Warn = func(msg string) {
+		result.Warnings = append(result.Warnings, msg)
+		if warn != nil {
+			warn(msg)
+		}
+	}
+	for _, example := range doc.Examples {
+		var samples []parse.Node
+		for _, value := range example.Values {
+			if value = bytes.TrimSpace(value); value[0] != '{' && value[0] != '[' {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("skipping example of %v: expected an object or an array", example.Name))
+				continue
+			}
+			sample, err := parse.ParseFromStringWithOptions(string(value), parseOpts)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing example of %v: %v", example.Name, err)
+			}
+			samples = append(samples, sample)
+		}
+
+		if len(samples) == 0 {
+			continue
+		}
+
+		exampleOpts := opts
+		exampleOpts.TypeName = sg.reserveName(makeVarname(example.Name))
+		g := &Generator{
+			file: file,
+			opts: exampleOpts,
+		}
+		if _, err := g.start(inferGoType(samples)); err != nil {
+			return nil, fmt.Errorf("error generating %v: %v", exampleOpts.TypeName, err)
+		}
+		file.Line()
+	}
+
+	code, err := generateOutput(file)
+	if err != nil {
+		return nil, err
+	}
+	result.Code = code
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/openapi"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+func TestOpenAPIFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "openapi"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			doc, err := openapi.Parse([]byte(readFile(filename)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := GenerateFromOpenAPI(doc, parse.Options{}, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+			typeCheck(t, result.Code)
+		})
+	}
+}
+
+func TestOpenAPINameCollision(t *testing.T) {
+	doc, err := openapi.Parse([]byte(`{
+		"openapi": "3.1.0",
+		"paths": {
+			"/users": {
+				"post": {
+					"operationId": "createUser",
+					"requestBody": { "content": { "application/json": { "example": { "name": "Jane" } } } }
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"CreateUserRequest": { "type": "object", "properties": { "name": { "type": "string" } } }
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := GenerateFromOpenAPI(doc, parse.Options{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Code, "type CreateUserRequest2 struct") {
+		t.Errorf("GenerateFromOpenAPI(): expected the example type to be renamed, got\n%v", result.Code)
+	}
+	typeCheck(t, result.Code)
+}
+
+func TestOpenAPISkipsPrimitiveExamples(t *testing.T) {
+	doc, err := openapi.Parse([]byte(`{
+		"openapi": "3.0.0",
+		"paths": {
+			"/health": {
+				"get": {
+					"responses": {
+						"200": { "content": { "application/json": { "example": "ok" } } }
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := GenerateFromOpenAPI(doc, parse.Options{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"skipping example of GetHealth200Response: expected an object or an array"}; !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("GenerateFromOpenAPI(): got warnings %q, want %q", result.Warnings, want)
+	}
+	if result.Code != "package generated\n" {
+		t.Errorf("GenerateFromOpenAPI(): got code %q, want an empty file", result.Code)
+	}
+}
@@ -0,0 +1,128 @@
+package generator
+
+import "fmt"
+
+const (
+	defaultPackageName = "generated"
+	defaultTypeName    = "JSONToStruct"
+)
+
+// Options configures Generate and GenerateFromString. The zero value generates the same output as the CLI
+// without any flags.
+type Options struct {
+	// PackageName is the name of the package of the generated file. Defaults to "generated".
+	PackageName string
+	// TypeName is the name of the generated root type. Defaults to "JSONToStruct".
+	TypeName string
+	// FieldOrder decides the order of the fields of generated structs.
+	FieldOrder FieldOrder
+	// Format is the output format. Defaults to Go type definitions.
+	Format Format
+	// Tags configures further struct tags besides json, e.g. yaml for types that are decoded from YAML. If a
+	// Tag appears more than once, the last one wins. validate tags are added by ValidateTags instead.
+	Tags []Tag
+	// ValidateTags adds `validate:"required"` tags for github.com/go-playground/validator to the fields whose
+	// keys were present in every sample. Only Go output supports it.
+	ValidateTags bool
+	// ValidateMethod adds a Validate method to the root type, which checks the required fields and the values
+	// of strings that looked like enums. Only Go output supports it.
+	ValidateMethod bool
+	// Examples adds a comment with an example value to the fields of primitives and arrays of primitives, e.g.
+	// `// e.g. "active"`. Only Go output supports it.
+	Examples bool
+	// ExampleStats adds a comment with how often a field was observed and how often it was null, e.g.
+	// `// seen 3 times, 33% null`. Only Go output supports it.
+	ExampleStats bool
+	// TypeOverrides maps keys to the Go type of their fields, replacing the inferred one wherever the key occurs,
+	// e.g. "id" to "string". Types of other packages are qualified by their import path, e.g. "time.Time" or
+	// "github.com/google/uuid.UUID", and may be prefixed by * or []. Fields with an overridden type aren't
+	// validated. Only Go output supports it.
+	TypeOverrides map[string]string
+}
+
+// needsValues reports whether the generated code depends on the values of the samples, which the parser only
+// keeps if parse.Options.KeepValues is set.
+func (o Options) needsValues() bool {
+	return o.ValidateTags || o.ValidateMethod || o.Examples
+}
+
+func (o Options) withDefaults() Options {
+	if o.PackageName == "" {
+		o.PackageName = defaultPackageName
+	}
+	if o.TypeName == "" {
+		o.TypeName = defaultTypeName
+	}
+	return o
+}
+
+// FieldOrder decides the order of the fields of generated structs.
+type FieldOrder int
+
+const (
+	// FieldOrderAlphabetical sorts the fields by their key.
+	FieldOrderAlphabetical FieldOrder = iota
+	// FieldOrderSource keeps the order in which the keys first appear in the input. Fields of merged objects
+	// are ordered by their first appearance in any of the objects.
+	FieldOrderSource
+)
+
+var fieldOrderNames = map[FieldOrder]string{
+	FieldOrderAlphabetical: "alpha",
+	FieldOrderSource:       "source",
+}
+
+func (o FieldOrder) String() string {
+	if name, ok := fieldOrderNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("FieldOrder(%d)", int(o))
+}
+
+// ParseFieldOrder returns the FieldOrder for its name as used by the CLI, i.e. "alpha" or "source".
+func ParseFieldOrder(name string) (FieldOrder, error) {
+	for order, orderName := range fieldOrderNames {
+		if orderName == name {
+			return order, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid field order %q. expected alpha or source", name)
+}
+
+// Format is the output format of Generate.
+type Format int
+
+const (
+	// FormatGo generates Go type definitions.
+	FormatGo Format = iota
+	// FormatJSONSchema generates a JSON Schema (draft 2020-12) describing the input.
+	FormatJSONSchema
+	// FormatTypeScript generates TypeScript declarations.
+	FormatTypeScript
+	// FormatProto generates a proto3 file with a message describing the input.
+	FormatProto
+)
+
+var formatNames = map[Format]string{
+	FormatGo:         "go",
+	FormatJSONSchema: "jsonschema",
+	FormatTypeScript: "typescript",
+	FormatProto:      "proto",
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the Format for its name as used by the CLI, e.g. "go" or "typescript".
+func ParseFormat(name string) (Format, error) {
+	for format, formatName := range formatNames {
+		if formatName == name {
+			return format, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid format %q. expected go, jsonschema, typescript or proto", name)
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// makeFieldType returns the type of field, which is its entry in TypeOverrides if it has one.
+func (g *Generator) makeFieldType(field *model.Field) *jen.Statement {
+	if override, ok := g.opts.TypeOverrides[field.Key]; ok {
+		t, err := parseGoType(override)
+		if err != nil {
+			// The overrides were validated before generating.
+			panic(err)
+		}
+		return t
+	}
+	return g.makeType(field.Type)
+}
+
+// isOverridden reports whether the type of field is replaced by an entry of TypeOverrides.
+func (g *Generator) isOverridden(field *model.Field) bool {
+	_, ok := g.opts.TypeOverrides[field.Key]
+	return ok
+}
+
+func validateTypeOverrides(overrides map[string]string) error {
+	for key, override := range overrides {
+		if _, err := parseGoType(override); err != nil {
+			return fmt.Errorf("invalid type override for %q: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// parseGoType parses a type as written in TypeOverrides, i.e. a predeclared type or a type of another package
+// qualified by its import path, optionally prefixed by any number of * and [], e.g. `[]*time.Time` or
+// `github.com/google/uuid.UUID`.
+func parseGoType(s string) (*jen.Statement, error) {
+	t := jen.Null()
+	rest := s
+	for {
+		if strings.HasPrefix(rest, "*") {
+			t.Op("*")
+			rest = rest[1:]
+		} else if strings.HasPrefix(rest, "[]") {
+			t.Index()
+			rest = rest[2:]
+		} else {
+			break
+		}
+	}
+
+	dot := strings.LastIndex(rest, ".")
+	if dot < 0 {
+		if !identifierIsPredeclared(rest) {
+			return nil, fmt.Errorf("%q is neither predeclared nor qualified by an import path", s)
+		}
+		return t.Id(rest), nil
+	}
+	path, name := rest[:dot], rest[dot+1:]
+	if path == "" || strings.ContainsAny(path, " \"`\\") {
+		return nil, fmt.Errorf("invalid import path %q", path)
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return nil, fmt.Errorf("%q isn't an exported type name", name)
+	}
+	return t.Qual(path, name), nil
+}
+
+// importPathOf returns the import path of a type as written in TypeOverrides, or "" for predeclared types.
+func importPathOf(override string) string {
+	override = strings.TrimLeft(override, "*[]")
+	if dot := strings.LastIndex(override, "."); dot >= 0 {
+		return override[:dot]
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+func TestParseGoType(t *testing.T) {
+	tests := []struct {
+		override string
+		want     string
+		wantErr  bool
+	}{
+		{override: "string", want: "string"},
+		{override: "*time.Time", want: "*time.Time"},
+		{override: "[]*github.com/google/uuid.UUID", want: "[]*uuid.UUID"},
+		{override: "Time", wantErr: true},
+		{override: "time.time", wantErr: true},
+		{override: ".Time", wantErr: true},
+		{override: "[]", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseGoType(test.override)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseGoType(%q): got error %v, want error %v", test.override, err, test.wantErr)
+			continue
+		}
+		if err == nil && got.GoString() != test.want {
+			t.Errorf("parseGoType(%q): got %v, want %v", test.override, got.GoString(), test.want)
+		}
+	}
+}
+
+func TestTypeOverrides(t *testing.T) {
+	input := `{"id": 1, "created_at": "2024-01-02T03:04:05Z", "owner": {"id": 2}}`
+	opts := Options{
+		FieldOrder:     FieldOrderSource,
+		ValidateMethod: true,
+		TypeOverrides:  map[string]string{"id": "string", "created_at": "time.Time"},
+	}
+	result, err := GenerateFromString(input, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `package generated
+
+import "time"
+
+type JSONToStruct struct {
+	Id         string    ` + "`json:\"id\"`" + `
+	Created_at time.Time ` + "`json:\"created_at\"`" + `
+	Owner      struct {
+		Id string ` + "`json:\"id\"`" + `
+	} ` + "`json:\"owner\"`" + `
+}
+
+// Validate checks that the fields present in every sample are set, and that strings that only
+// had a few distinct values have one of them.
+func (j JSONToStruct) Validate() error {
+	return nil
+}
+`
+	if result.Code != expected {
+		t.Errorf("Diff: \n\n%v", diff.Diff(result.Code, expected))
+	}
+
+	opts.TypeOverrides = map[string]string{"id": "uuid"}
+	if _, err := GenerateFromString(input, opts); err == nil {
+		t.Errorf("expected an error for an invalid override")
+	}
+}
+
+func TestUpdateTypeOverrides(t *testing.T) {
+	existing := "package api\n\ntype User struct {\n\tID int `json:\"id\"`\n}\n"
+	tree, err := parse.ParseFromString(`{"id": 1.5, "created_at": "2024-01-02T03:04:05Z"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{TypeName: "User", TypeOverrides: map[string]string{"id": "int", "created_at": "time.Time"}}
+	result, err := Update(existing, tree, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`import "time"`, "Created_at time.Time `json:\"created_at\"`"} {
+		if !strings.Contains(result.Code, want) {
+			t.Errorf("Update(): %q is missing in\n%v", want, result.Code)
+		}
+	}
+	if strings.Contains(result.Code, "float64") {
+		t.Errorf("Update(): the overridden type was widened in\n%v", result.Code)
+	}
+	typeCheck(t, result.Code)
+}
@@ -0,0 +1,238 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+)
+
+const (
+	protoIndent        = "  "
+	protoTimestamp     = "google.protobuf.Timestamp"
+	protoStruct        = "google.protobuf.Struct"
+	protoValue         = "google.protobuf.Value"
+	protoListValue     = "google.protobuf.ListValue"
+	protoTimestampFile = "google/protobuf/timestamp.proto"
+	protoStructFile    = "google/protobuf/struct.proto"
+)
+
+// protoMessage is a message of the generated .proto file. Nested objects become nested messages, just like
+// the anonymous structs of the Go output.
+type protoMessage struct {
+	name    string
+	comment string
+	fields  []protoField
+	nested  []*protoMessage
+}
+
+type protoField struct {
+	label    string
+	typ      string
+	name     string
+	jsonName string
+}
+
+// protoGenerator holds the state of a single run of generateProto.
+type protoGenerator struct {
+	opts    Options
+	imports map[string]bool
+}
+
+// generateProto renders root as a proto3 file. Since messages can't be arrays, a root array of objects is
+// described by a message for its elements.
+func generateProto(root *model.Type, opts Options) (*Result, error) {
+	g := &protoGenerator{opts: opts, imports: make(map[string]bool)}
+
+	var message *protoMessage
+	switch root.Kind {
+	case model.KindObject:
+		message = g.makeMessage(opts.TypeName, root)
+	case model.KindArray:
+		if elem := root.Elem.WithoutNull(); elem.Kind == model.KindObject {
+			message = g.makeMessage(opts.TypeName, elem)
+			message.comment = fmt.Sprintf("%v is an element of the top-level array.", opts.TypeName)
+			break
+		}
+		return nil, fmt.Errorf("can't generate a message for an array that doesn't contain objects")
+	default:
+		return nil, fmt.Errorf("invalid json. expected { or [ as initial node but received something else")
+	}
+
+	code := &strings.Builder{}
+	code.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(code, "package %v;\n\n", opts.PackageName)
+	if len(g.imports) > 0 {
+		var imports []string
+		for file := range g.imports {
+			imports = append(imports, file)
+		}
+		sort.Strings(imports)
+		for _, file := range imports {
+			fmt.Fprintf(code, "import %q;\n", file)
+		}
+		code.WriteString("\n")
+	}
+	writeProtoMessage(code, message, 0)
+	return &Result{Code: code.String()}, nil
+}
+
+func (g *protoGenerator) makeMessage(name string, obj *model.Type) *protoMessage {
+	message := &protoMessage{name: name}
+	usedFieldNames := make(map[string]bool)
+	usedMessageNames := make(map[string]bool)
+	for _, field := range orderFields(obj, g.opts.FieldOrder) {
+		fieldName := reserveProtoName(usedFieldNames, makeProtoFieldName(field.Key), "_")
+		pf := protoField{name: fieldName}
+		if jsonName(fieldName) != field.Key {
+			pf.jsonName = field.Key
+		}
+
+		t := field.Type.WithoutNull()
+		elem := t
+		if t.Kind == model.KindArray {
+			elem = t.Elem.WithoutNull()
+			pf.label = "repeated"
+		} else if field.Optional || field.Type.Nullable() {
+			pf.label = "optional"
+		}
+
+		if elem.Kind == model.KindObject && len(elem.Fields) > 0 {
+			nestedName := reserveProtoName(usedMessageNames, makeProtoMessageName(fieldName), "")
+			message.nested = append(message.nested, g.makeMessage(nestedName, elem))
+			pf.typ = nestedName
+		} else {
+			pf.typ = g.makeScalarType(elem, pf.label == "repeated")
+		}
+		message.fields = append(message.fields, pf)
+	}
+	return message
+}
+
+// makeScalarType returns the type of a value that doesn't need a message of its own. Values that can't be
+// described more precisely fall back to the well-known types of google/protobuf/struct.proto.
+func (g *protoGenerator) makeScalarType(t *model.Type, isRepeated bool) string {
+	switch t.Kind {
+	case model.KindString:
+		if t.Time {
+			g.imports[protoTimestampFile] = true
+			return protoTimestamp
+		}
+		return "string"
+	case model.KindInteger:
+		return "int64"
+	case model.KindFloat:
+		return "double"
+	case model.KindBool:
+		return "bool"
+	case model.KindUnion:
+		// Integers observed together with floats are widened, just like JSON Schema's number type.
+		if len(t.Variants) == 2 && t.Variant(model.KindInteger) != nil && t.Variant(model.KindFloat) != nil {
+			return "double"
+		}
+	case model.KindObject:
+		// Objects that were always empty could contain anything.
+		g.imports[protoStructFile] = true
+		return protoStruct
+	case model.KindArray:
+		// Repeated fields can't be nested, so only the outer array can be repeated.
+		if isRepeated {
+			g.imports[protoStructFile] = true
+			return protoListValue
+		}
+	}
+	g.imports[protoStructFile] = true
+	return protoValue
+}
+
+func writeProtoMessage(code *strings.Builder, message *protoMessage, depth int) {
+	indent := strings.Repeat(protoIndent, depth)
+	if message.comment != "" {
+		fmt.Fprintf(code, "%v// %v\n", indent, message.comment)
+	}
+	fmt.Fprintf(code, "%vmessage %v {\n", indent, message.name)
+	for i, field := range message.fields {
+		code.WriteString(indent + protoIndent)
+		if field.label != "" {
+			code.WriteString(field.label + " ")
+		}
+		fmt.Fprintf(code, "%v %v = %v", field.typ, field.name, i+1)
+		if field.jsonName != "" {
+			fmt.Fprintf(code, " [json_name = %v]", strconv.Quote(field.jsonName))
+		}
+		code.WriteString(";\n")
+	}
+	for _, nested := range message.nested {
+		code.WriteString("\n")
+		writeProtoMessage(code, nested, depth+1)
+	}
+	code.WriteString(indent + "}\n")
+}
+
+// makeProtoFieldName turns key into a lower_snake_case identifier, e.g. `created_at` for `createdAt`.
+// Identifiers in .proto files consist of ASCII letters, digits and underscores and start with a letter.
+func makeProtoFieldName(key string) string {
+	name := &strings.Builder{}
+	var previous rune
+	for _, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if (previous >= 'a' && previous <= 'z') || (previous >= '0' && previous <= '9') {
+				name.WriteRune('_')
+			}
+			name.WriteRune(r - 'A' + 'a')
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			name.WriteRune(r)
+		default:
+			if previous != '_' && name.Len() > 0 {
+				name.WriteRune('_')
+			}
+			r = '_'
+		}
+		previous = r
+	}
+
+	fieldName := strings.TrimSuffix(name.String(), "_")
+	if fieldName == "" || fieldName[0] < 'a' {
+		fieldName = "x" + fieldName
+	}
+	return fieldName
+}
+
+// makeProtoMessageName turns a field name into an UpperCamelCase identifier, e.g. `CreatedAt` for `created_at`.
+func makeProtoMessageName(fieldName string) string {
+	name := jsonName(fieldName)
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// jsonName returns the JSON name protoc derives from a field name: underscores are dropped, and the letters
+// following them are upper cased.
+func jsonName(fieldName string) string {
+	name := &strings.Builder{}
+	upperNext := false
+	for _, r := range fieldName {
+		switch {
+		case r == '_':
+			upperNext = true
+		case upperNext && r >= 'a' && r <= 'z':
+			name.WriteRune(r - 'a' + 'A')
+			upperNext = false
+		default:
+			name.WriteRune(r)
+			upperNext = false
+		}
+	}
+	return name.String()
+}
+
+// reserveProtoName returns name, or name with a numeric suffix if it's already used.
+func reserveProtoName(used map[string]bool, name, separator string) string {
+	uniqueName := name
+	for i := 2; used[uniqueName]; i++ {
+		uniqueName = fmt.Sprintf("%s%s%d", name, separator, i)
+	}
+	used[uniqueName] = true
+	return uniqueName
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+)
+
+func TestProtoFormatFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "to_proto"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			result, err := GenerateFromString(readFile(filename), Options{Format: FormatProto})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+		})
+	}
+}
+
+func TestMakeProtoFieldName(t *testing.T) {
+	tests := []struct {
+		key      string
+		want     string
+		jsonName string
+	}{
+		{key: "name", want: "name", jsonName: "name"},
+		{key: "createdAt", want: "created_at", jsonName: "createdAt"},
+		{key: "created_at", want: "created_at", jsonName: "createdAt"},
+		{key: "HTTPStatus", want: "httpstatus", jsonName: "httpstatus"},
+		{key: "content-type", want: "content_type", jsonName: "contentType"},
+		{key: "$schema", want: "schema", jsonName: "schema"},
+		{key: "2fa", want: "x2fa", jsonName: "x2fa"},
+		{key: "café", want: "caf", jsonName: "caf"},
+		{key: "", want: "x", jsonName: "x"},
+	}
+	for _, test := range tests {
+		got := makeProtoFieldName(test.key)
+		if got != test.want {
+			t.Errorf("makeProtoFieldName(%q): got %v, want %v", test.key, got, test.want)
+		}
+		if jsonName(got) != test.jsonName {
+			t.Errorf("jsonName(%q): got %v, want %v", got, jsonName(got), test.jsonName)
+		}
+	}
+}
+
+func TestProtoRootArrayWithoutObjects(t *testing.T) {
+	if _, err := GenerateFromString(`[1, 2]`, Options{Format: FormatProto}); err == nil {
+		t.Errorf("expected an error for a root array of numbers")
+	}
+}
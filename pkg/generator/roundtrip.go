@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// GenerateTest generates a test for the Go types Generate generates for sample, which has to be plain JSON. The
+// test decodes sample into the root type, rejecting unknown fields, encodes the result again and checks that
+// no value got lost on the way, which tells whether the inferred types actually fit the data.
+func GenerateTest(sample string, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	if !token.IsIdentifier(opts.TypeName) {
+		return nil, fmt.Errorf("invalid type name %q", opts.TypeName)
+	}
+	if !token.IsIdentifier(opts.PackageName) {
+		return nil, fmt.Errorf("invalid package name %q", opts.PackageName)
+	}
+	if opts.Format != FormatGo {
+		return nil, fmt.Errorf("can't generate a test for format %v", opts.Format)
+	}
+
+	// encoding/json rejects byte order marks, which the parser skips.
+	sample = strings.TrimPrefix(sample, "\ufeff")
+
+	file := jen.NewFile(opts.PackageName)
+	testName := "Test" + opts.TypeName + "RoundTrip"
+	compareName := "compare" + opts.TypeName + "Values"
+
+	file.Commentf("%v checks that the sample %v was generated from decodes without unknown", testName, opts.TypeName)
+	file.Comment("fields, and that encoding it again keeps all of its values.")
+	file.Func().Id(testName).Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(
+		jen.Const().Id("sample").Op("=").Add(makeStringLiteral(sample)),
+		jen.Line(),
+		jen.Id("decoder").Op(":=").Qual("encoding/json", "NewDecoder").Call(
+			jen.Qual("strings", "NewReader").Call(jen.Id("sample")),
+		),
+		jen.Id("decoder").Dot("DisallowUnknownFields").Call(),
+		jen.Var().Id("decoded").Id(opts.TypeName),
+		jen.If(jen.Err().Op(":=").Id("decoder").Dot("Decode").Call(jen.Op("&").Id("decoded")), jen.Err().Op("!=").Nil()).Block(
+			jen.Id("t").Dot("Fatalf").Call(jen.Lit("decoding the sample failed: %v"), jen.Err()),
+		),
+		jen.List(jen.Id("encoded"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(jen.Id("decoded")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(
+			jen.Id("t").Dot("Fatalf").Call(jen.Lit("encoding the decoded sample failed: %v"), jen.Err()),
+		),
+		jen.Line(),
+		jen.Var().List(jen.Id("want"), jen.Id("got")).Interface(),
+		jen.If(jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(jen.Index().Byte().Parens(jen.Id("sample")), jen.Op("&").Id("want")), jen.Err().Op("!=").Nil()).Block(
+			jen.Id("t").Dot("Fatal").Call(jen.Err()),
+		),
+		jen.If(jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(jen.Id("encoded"), jen.Op("&").Id("got")), jen.Err().Op("!=").Nil()).Block(
+			jen.Id("t").Dot("Fatal").Call(jen.Err()),
+		),
+		jen.Id(compareName).Call(jen.Id("t"), jen.Lit("sample"), jen.Id("want"), jen.Id("got")),
+	)
+	file.Line()
+	file.Add(makeCompareFunc(compareName))
+
+	code, err := generateOutput(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Code: code}, nil
+}
+
+// makeStringLiteral returns s as a raw string literal if possible, which keeps embedded JSON readable.
+// gofmt drops carriage returns from raw strings and the compiler rejects NULs and byte order marks, so those
+// have to be quoted.
+func makeStringLiteral(s string) *jen.Statement {
+	if strings.ContainsAny(s, "`\r\x00\ufeff") || !utf8.ValidString(s) {
+		return jen.Lit(s)
+	}
+	return jen.Op("`" + s + "`")
+}
+
+// makeCompareFunc returns the function of the generated test comparing the decoded sample to the encoded one.
+// Since the generated fields don't tell missing keys and nulls apart from zero values, those may come back as
+// zero values.
+func makeCompareFunc(name string) *jen.Statement {
+	recurse := func(path *jen.Statement, want, got jen.Code) *jen.Statement {
+		return jen.Id(name).Call(jen.Id("t"), path, want, got)
+	}
+	keyPath := jen.Id("path").Op("+").Lit(".").Op("+").Id("key")
+
+	return jen.Commentf("%v reports the values of want that got lacks. Keys missing from want and nulls may", name).Line().
+		Comment("come back as zero values, since the generated fields can't tell them apart.").Line().
+		Func().Id(name).Params(
+		jen.Id("t").Op("*").Qual("testing", "T"),
+		jen.Id("path").String(),
+		jen.List(jen.Id("want"), jen.Id("got")).Interface(),
+	).Block(
+		jen.Id("t").Dot("Helper").Call(),
+		jen.Switch(jen.Id("want").Op(":=").Id("want").Assert(jen.Type())).Block(
+			jen.Case(jen.Nil()).Block(
+				jen.If(jen.List(jen.Id("object"), jen.Id("ok")).Op(":=").Id("got").Assert(jen.Map(jen.String()).Interface()), jen.Id("ok")).Block(
+					jen.For(jen.List(jen.Id("key"), jen.Id("value")).Op(":=").Range().Id("object")).Block(
+						recurse(keyPath.Clone(), jen.Nil(), jen.Id("value")),
+					),
+				).Else().If(
+					jen.Id("got").Op("!=").Nil().Op("&&").
+						Id("got").Op("!=").False().Op("&&").
+						Id("got").Op("!=").Lit("").Op("&&").
+						Id("got").Op("!=").Lit(0.0),
+				).Block(
+					jen.Id("t").Dot("Errorf").Call(jen.Lit("%v: got %v, want null or a zero value"), jen.Id("path"), jen.Id("got")),
+				),
+			),
+			jen.Case(jen.Map(jen.String()).Interface()).Block(
+				jen.List(jen.Id("object"), jen.Id("ok")).Op(":=").Id("got").Assert(jen.Map(jen.String()).Interface()),
+				jen.If(jen.Op("!").Id("ok")).Block(
+					jen.Id("t").Dot("Errorf").Call(jen.Lit("%v: got %v, want an object"), jen.Id("path"), jen.Id("got")),
+					jen.Return(),
+				),
+				jen.For(jen.List(jen.Id("key"), jen.Id("value")).Op(":=").Range().Id("want")).Block(
+					recurse(keyPath.Clone(), jen.Id("value"), jen.Id("object").Index(jen.Id("key"))),
+				),
+				jen.For(jen.List(jen.Id("key"), jen.Id("value")).Op(":=").Range().Id("object")).Block(
+					jen.If(jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("want").Index(jen.Id("key")), jen.Op("!").Id("ok")).Block(
+						recurse(keyPath.Clone(), jen.Nil(), jen.Id("value")),
+					),
+				),
+			),
+			jen.Case(jen.Index().Interface()).Block(
+				jen.List(jen.Id("array"), jen.Id("ok")).Op(":=").Id("got").Assert(jen.Index().Interface()),
+				jen.If(jen.Op("!").Id("ok").Op("||").Len(jen.Id("array")).Op("!=").Len(jen.Id("want"))).Block(
+					jen.Id("t").Dot("Errorf").Call(jen.Lit("%v: got %v, want %v"), jen.Id("path"), jen.Id("got"), jen.Id("want")),
+					jen.Return(),
+				),
+				jen.For(jen.Id("i").Op(":=").Range().Id("want")).Block(
+					recurse(
+						jen.Qual("fmt", "Sprintf").Call(jen.Lit("%v[%d]"), jen.Id("path"), jen.Id("i")),
+						jen.Id("want").Index(jen.Id("i")),
+						jen.Id("array").Index(jen.Id("i")),
+					),
+				),
+			),
+			jen.Default().Block(
+				jen.If(jen.Op("!").Qual("reflect", "DeepEqual").Call(jen.Id("want"), jen.Id("got"))).Block(
+					jen.Id("t").Dot("Errorf").Call(jen.Lit("%v: got %v, want %v"), jen.Id("path"), jen.Id("got"), jen.Id("want")),
+				),
+			),
+		),
+	)
+}
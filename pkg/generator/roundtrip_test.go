@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTestFiles(t *testing.T) {
+	// Type checking the imports of the tests from source is slow, so only a few files are checked.
+	for _, filename := range []string{"1", "big_reddit_response", "line_endings_crlf_bom", "string_2_escaped_invalid_codepoints"} {
+		filename := path.Join(dirName, filename)
+		t.Run(path.Base(filename), func(t *testing.T) {
+			sample := readFile(filename)
+			types, err := GenerateFromString(sample, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			test, err := GenerateTest(sample, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			typeCheck(t, types.Code, test.Code)
+		})
+	}
+}
+
+func TestGenerateTestRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("running go test is slow")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+
+	tests := []struct {
+		name     string
+		sample   string
+		wantFail string
+	}{
+		{
+			name:   "merged objects",
+			sample: "[{ \"id\": 1, \"tags\": [\"a\"] }, { \"id\": 2, \"name\": \"b`c\", \"parent\": null }]",
+		},
+		{
+			name:     "number too large",
+			sample:   `{ "id": 10000000000000000999 }`,
+			wantFail: "cannot unmarshal number 10000000000000000999",
+		},
+		{
+			name:     "duplicate keys",
+			sample:   `{ "a": { "b": 1 }, "a": { "c": 2 } }`,
+			wantFail: "sample.a.b: got 1, want null or a zero value",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			types, err := GenerateFromString(test.sample, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			roundTrip, err := GenerateTest(test.sample, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "go.mod"), "module generated\n")
+			writeFile(t, filepath.Join(dir, "generated.go"), types.Code)
+			writeFile(t, filepath.Join(dir, "generated_test.go"), roundTrip.Code)
+			cmd := exec.Command("go", "test", ".")
+			cmd.Dir = dir
+			output, err := cmd.CombinedOutput()
+
+			switch {
+			case test.wantFail == "" && err != nil:
+				t.Errorf("generated test failed: %s", output)
+			case test.wantFail != "" && !strings.Contains(string(output), test.wantFail):
+				t.Errorf("generated test should have failed with %q, got %s", test.wantFail, output)
+			}
+		})
+	}
+}
+
+func TestGenerateTestInvalidOptions(t *testing.T) {
+	if _, err := GenerateTest(`{}`, Options{Format: FormatTypeScript}); err == nil {
+		t.Errorf("expected an error for TypeScript output")
+	}
+	if _, err := GenerateTest(`{}`, Options{TypeName: "not valid"}); err == nil {
+		t.Errorf("expected an error for an invalid type name")
+	}
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
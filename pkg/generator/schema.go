@@ -0,0 +1,488 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/marhaupe/json2struct/pkg/jsonschema"
+)
+
+// GenerateFromSchema generates Go type definitions for a JSON Schema document instead of a sample. The root
+// schema becomes the type named opts.TypeName, and every schema in `$defs` or `definitions` as well as every
+// other `$ref` target becomes a named type of its own. Documents without a root schema only get the named types.
+//
+// Properties that aren't required get the `omitempty` option, and they are pointers just like nullable
+// properties and properties of recursive types, unless their type can be nil anyway. String enums become named
+// types with a constant per value, and `oneOf`/`anyOf` become sum types: structs with a pointer field per
+// variant, of which exactly one is set after unmarshalling.
+func GenerateFromSchema(doc *jsonschema.Document, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	if opts.Format != FormatGo {
+		return nil, fmt.Errorf("generating %v from a JSON Schema isn't supported", opts.Format)
+	}
+	if err := validateOptions(opts); err != nil {
+		return nil, err
+	}
+	if unsupported := sampleOnlyOptions(opts); len(unsupported) > 0 {
+		return nil, fmt.Errorf("types generated from a JSON Schema don't support %v", strings.Join(unsupported, ", "))
+	}
+	g := &schemaGenerator{
+		doc:       doc,
+		file:      jen.NewFile(opts.PackageName),
+		opts:      opts,
+		typeNames: make(map[string]string),
+		usedNames: make(map[string]bool),
+	}
+	generatedFile, err := g.start()
+	if err != nil {
+		return nil, err
+	}
+	code, err := generateOutput(generatedFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Code: code}, nil
+}
+
+type schemaGenerator struct {
+	doc  *jsonschema.Document
+	file *jen.File
+	opts Options
+
+	// typeNames maps refs to the names of their generated types.
+	typeNames map[string]string
+	usedNames map[string]bool
+	// pendingRefs are refs that have been assigned a type name, but whose type hasn't been generated yet.
+	pendingRefs []string
+	// currentRef is the ref of the named type that is currently generated.
+	currentRef string
+	// helpers are the enum and sum types needed by the type that is currently generated.
+	helpers []jen.Code
+}
+
+func (g *schemaGenerator) start() (file *jen.File, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			file = nil
+			err = errors.New(fmt.Sprint(r))
+		}
+	}()
+
+	if g.doc.Root != nil {
+		g.typeNames["#"] = g.reserveName(g.opts.TypeName)
+	}
+	for _, ref := range g.doc.Definitions() {
+		g.typeName(ref)
+	}
+
+	if g.doc.Root != nil {
+		g.currentRef = "#"
+		g.makeNamedType(g.opts.TypeName, g.doc.Root)
+	}
+	for len(g.pendingRefs) > 0 {
+		ref := g.pendingRefs[0]
+		g.pendingRefs = g.pendingRefs[1:]
+		schema, err := g.doc.Resolve(ref)
+		if err != nil {
+			panic(err.Error())
+		}
+		g.currentRef = ref
+		g.makeNamedType(g.typeNames[ref], schema)
+	}
+	return g.file, nil
+}
+
+// makeNamedType declares a type called name for schema, followed by the enum and sum types it needs.
+func (g *schemaGenerator) makeNamedType(name string, schema *jsonschema.Schema) {
+	var decls []jen.Code
+	switch variants := nonNullVariants(schema); {
+	case isStringEnum(schema):
+		decls = g.makeEnum(name, schema)
+	case len(variants) > 1:
+		decls = g.makeSumType(name, variants)
+	default:
+		decls = []jen.Code{jen.Type().Id(name).Add(g.makeType(name, schema))}
+	}
+	if schema.Description != "" {
+		decls[0] = jen.Comment(schema.Description).Line().Add(decls[0])
+	}
+	decls = append(decls, g.helpers...)
+	g.helpers = nil
+
+	for _, decl := range decls {
+		g.file.Add(decl)
+		g.file.Line()
+	}
+}
+
+// makeType returns the Go type for schema. name is used to derive the names of the enum and sum types that
+// schema might need.
+func (g *schemaGenerator) makeType(name string, schema *jsonschema.Schema) *jen.Statement {
+	if schema.Ref != "" {
+		return jen.Id(g.typeName(schema.Ref))
+	}
+
+	if variants := nonNullVariants(schema); len(variants) > 0 {
+		if len(variants) == 1 {
+			return g.makeType(name, variants[0])
+		}
+		helperName := g.reserveName(name)
+		helpers := g.makeSumType(helperName, variants)
+		g.helpers = append(g.helpers, helpers...)
+		return jen.Id(helperName)
+	}
+
+	if len(schema.AllOf) > 0 {
+		return g.makeType(name, g.mergeAllOf(schema))
+	}
+
+	if isStringEnum(schema) {
+		helperName := g.reserveName(name)
+		helpers := g.makeEnum(helperName, schema)
+		g.helpers = append(g.helpers, helpers...)
+		return jen.Id(helperName)
+	}
+
+	switch kind := schemaKind(schema); kind {
+	case jsonschema.TypeObject:
+		return g.makeObject(name, schema)
+	case jsonschema.TypeArray:
+		if schema.Items == nil {
+			return jen.Index().Interface()
+		}
+		return jen.Index().Add(g.makeType(name+"Item", schema.Items))
+	case jsonschema.TypeString:
+		if schema.Format == "date-time" {
+			return jen.Qual("time", "Time")
+		}
+		return jen.String()
+	case jsonschema.TypeInteger:
+		switch schema.Format {
+		case "int32":
+			return jen.Int32()
+		case "int64":
+			return jen.Int64()
+		}
+		return jen.Int()
+	case jsonschema.TypeNumber:
+		if schema.Format == "float" {
+			return jen.Float32()
+		}
+		return jen.Float64()
+	case jsonschema.TypeBoolean:
+		return jen.Bool()
+	default:
+		return jen.Interface()
+	}
+}
+
+func (g *schemaGenerator) makeObject(name string, schema *jsonschema.Schema) *jen.Statement {
+	if schema.Properties.Len() == 0 {
+		if schema.AdditionalProperties != nil {
+			return jen.Map(jen.String()).Add(g.makeType(name+"Value", schema.AdditionalProperties))
+		}
+		return jen.Map(jen.String()).Interface()
+	}
+
+	keys := append([]string(nil), schema.Properties.Keys...)
+	if g.opts.FieldOrder != FieldOrderSource {
+		sort.Strings(keys)
+	}
+
+	var fields []jen.Code
+	for _, key := range keys {
+		property := g.resolveRef(schema.Properties.Schemas[key])
+		varname := makeVarname(key)
+		fieldType := g.makeType(name+varname, schema.Properties.Schemas[key])
+
+		isRequired := schema.IsRequired(key)
+		// A type can't contain itself, so a field that would contain the type being generated is a pointer
+		// even if it's required.
+		isRecursive := g.currentRef != "" && g.containsByValue(schema.Properties.Schemas[key], g.currentRef, make(map[string]bool))
+		if (!isRequired || isNullable(property) || isRecursive) && !g.isNilable(property) {
+			fieldType = jen.Op("*").Add(fieldType)
+		}
+		var tag *jen.Statement
+		if isRequired {
+			tag = makeTag(g.opts, key)
+		} else {
+			tag = makeTag(g.opts, key, "omitempty")
+		}
+		fields = append(fields, jen.Id(varname).Add(fieldType).Add(tag))
+	}
+	return jen.Struct(fields...)
+}
+
+// makeEnum declares the string type name together with a constant for each of its values.
+func (g *schemaGenerator) makeEnum(name string, schema *jsonschema.Schema) []jen.Code {
+	var constants []jen.Code
+	for _, value := range schema.Enum {
+		value := value.(string)
+		constName := g.reserveName(name + makeVarname(value))
+		constants = append(constants, jen.Id(constName).Id(name).Op("=").Lit(value))
+	}
+	return []jen.Code{
+		jen.Type().Id(name).String(),
+		jen.Const().Defs(constants...),
+	}
+}
+
+// makeSumType declares a struct with a pointer field for each variant, together with the methods that
+// (un)marshal the set variant. Unmarshalling picks the first variant that the data can be decoded into
+// without unknown fields.
+func (g *schemaGenerator) makeSumType(name string, variants []*jsonschema.Schema) []jen.Code {
+	var fields []jen.Code
+	var unmarshalVariants []jen.Code
+	var marshalCases []jen.Code
+	usedFieldNames := make(map[string]bool)
+
+	for i, variant := range variants {
+		fieldName := variantName(variant, i)
+		for usedFieldNames[fieldName] {
+			fieldName = fmt.Sprintf("%s%d", fieldName, i+1)
+		}
+		usedFieldNames[fieldName] = true
+
+		fieldType := g.makeType(name+fieldName, variant)
+		fields = append(fields, jen.Id(fieldName).Op("*").Add(fieldType))
+
+		unmarshalVariants = append(unmarshalVariants, jen.Block(
+			jen.Var().Id("variant").Add(fieldType),
+			jen.If(
+				jen.Id("decodeStrict").Call(jen.Id("data"), jen.Op("&").Id("variant")).Op("==").Nil(),
+			).Block(
+				jen.Id("v").Dot(fieldName).Op("=").Op("&").Id("variant"),
+				jen.Return(jen.Nil()),
+			),
+		))
+		marshalCases = append(marshalCases, jen.Case(jen.Id("v").Dot(fieldName).Op("!=").Nil()).Block(
+			jen.Return(jen.Qual("encoding/json", "Marshal").Call(jen.Id("v").Dot(fieldName))),
+		))
+	}
+
+	unmarshalBody := []jen.Code{
+		jen.Op("*").Id("v").Op("=").Id(name).Values(),
+		jen.Id("decodeStrict").Op(":=").Func().Params(jen.Id("data").Index().Byte(), jen.Id("target").Interface()).Error().Block(
+			jen.Id("dec").Op(":=").Qual("encoding/json", "NewDecoder").Call(jen.Qual("bytes", "NewReader").Call(jen.Id("data"))),
+			jen.Id("dec").Dot("DisallowUnknownFields").Call(),
+			jen.Return(jen.Id("dec").Dot("Decode").Call(jen.Id("target"))),
+		),
+	}
+	unmarshalBody = append(unmarshalBody, unmarshalVariants...)
+	unmarshalBody = append(unmarshalBody, jen.Return(
+		jen.Qual("fmt", "Errorf").Call(jen.Lit(name+": data matches none of the variants")),
+	))
+
+	return []jen.Code{
+		jen.Comment(fmt.Sprintf("%s holds exactly one of its variants.", name)).Line().
+			Type().Id(name).Struct(fields...),
+		jen.Func().Params(jen.Id("v").Op("*").Id(name)).Id("UnmarshalJSON").
+			Params(jen.Id("data").Index().Byte()).Error().Block(unmarshalBody...),
+		jen.Func().Params(jen.Id("v").Id(name)).Id("MarshalJSON").
+			Params().Params(jen.Index().Byte(), jen.Error()).Block(
+			jen.Switch().Block(marshalCases...),
+			jen.Return(jen.Index().Byte().Call(jen.Lit("null")), jen.Nil()),
+		),
+	}
+}
+
+// typeName returns the name of the type generated for ref, and schedules its generation if necessary.
+func (g *schemaGenerator) typeName(ref string) string {
+	if name, ok := g.typeNames[ref]; ok {
+		return name
+	}
+	name := g.reserveName(makeVarname(jsonschema.RefName(ref)))
+	g.typeNames[ref] = name
+	g.pendingRefs = append(g.pendingRefs, ref)
+	return name
+}
+
+// reserveName returns name, or name with a numeric suffix if it's already taken.
+func (g *schemaGenerator) reserveName(name string) string {
+	uniqueName := name
+	for i := 2; g.usedNames[uniqueName]; i++ {
+		uniqueName = fmt.Sprintf("%s%d", name, i)
+	}
+	g.usedNames[uniqueName] = true
+	return uniqueName
+}
+
+func (g *schemaGenerator) resolveRef(schema *jsonschema.Schema) *jsonschema.Schema {
+	for i := 0; schema.Ref != "" && i < 32; i++ {
+		resolved, err := g.doc.Resolve(schema.Ref)
+		if err != nil {
+			panic(err.Error())
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+// containsByValue reports whether the Go type for schema contains the type generated for ref without a pointer,
+// slice or map in between. visited holds the refs that have already been followed.
+func (g *schemaGenerator) containsByValue(schema *jsonschema.Schema, ref string, visited map[string]bool) bool {
+	if schema.Ref != "" {
+		if schema.Ref == ref {
+			return true
+		}
+		if visited[schema.Ref] {
+			return false
+		}
+		visited[schema.Ref] = true
+		resolved, err := g.doc.Resolve(schema.Ref)
+		if err != nil {
+			panic(err.Error())
+		}
+		return g.containsByValue(resolved, ref, visited)
+	}
+
+	// Sum types only have pointer fields.
+	if variants := nonNullVariants(schema); len(variants) > 0 {
+		return len(variants) == 1 && g.containsByValue(variants[0], ref, visited)
+	}
+	if len(schema.AllOf) > 0 {
+		return g.containsByValue(g.mergeAllOf(schema), ref, visited)
+	}
+	// Objects without properties are maps.
+	if isStringEnum(schema) || schemaKind(schema) != jsonschema.TypeObject || schema.Properties.Len() == 0 {
+		return false
+	}
+	for _, key := range schema.Properties.Keys {
+		propertySchema := schema.Properties.Schemas[key]
+		property := g.resolveRef(propertySchema)
+		if !schema.IsRequired(key) || isNullable(property) || g.isNilable(property) {
+			continue
+		}
+		if g.containsByValue(propertySchema, ref, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNilable reports whether the Go type for schema can be nil without being a pointer.
+func (g *schemaGenerator) isNilable(schema *jsonschema.Schema) bool {
+	schema = g.resolveRef(schema)
+	if variants := nonNullVariants(schema); len(variants) > 0 {
+		return len(variants) == 1 && g.isNilable(variants[0])
+	}
+	if len(schema.AllOf) > 0 || isStringEnum(schema) {
+		return false
+	}
+	switch schemaKind(schema) {
+	case jsonschema.TypeArray, "":
+		return true
+	case jsonschema.TypeObject:
+		return schema.Properties.Len() == 0
+	default:
+		return false
+	}
+}
+
+// schemaKind returns the single non-null type of schema, inferring it from other keywords if necessary.
+// It returns the empty string if the type can't be narrowed down to one.
+func schemaKind(schema *jsonschema.Schema) string {
+	types := schema.Type.WithoutNull()
+	switch {
+	case len(types) == 1:
+		return types[0]
+	case len(types) > 1:
+		return ""
+	case schema.Properties.Len() > 0 || schema.AdditionalProperties != nil:
+		return jsonschema.TypeObject
+	case schema.Items != nil:
+		return jsonschema.TypeArray
+	default:
+		return ""
+	}
+}
+
+func isNullable(schema *jsonschema.Schema) bool {
+	if schema.IsNullable() {
+		return true
+	}
+	for _, variant := range append(schema.OneOf, schema.AnyOf...) {
+		if variant.Type.Contains(jsonschema.TypeNull) && len(variant.Type) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNullVariants returns the variants of a oneOf or anyOf schema, leaving out the ones that only allow null.
+func nonNullVariants(schema *jsonschema.Schema) []*jsonschema.Schema {
+	var variants []*jsonschema.Schema
+	for _, variant := range append(schema.OneOf, schema.AnyOf...) {
+		if len(variant.Type) == 1 && variant.Type[0] == jsonschema.TypeNull {
+			continue
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+func isStringEnum(schema *jsonschema.Schema) bool {
+	if len(schema.Enum) == 0 {
+		return false
+	}
+	for _, value := range schema.Enum {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeAllOf combines the properties of the subschemas of an allOf schema into one object schema.
+func (g *schemaGenerator) mergeAllOf(schema *jsonschema.Schema) *jsonschema.Schema {
+	merged := &jsonschema.Schema{
+		Type:     jsonschema.TypeList{jsonschema.TypeObject},
+		Required: append([]string(nil), schema.Required...),
+	}
+	properties := &jsonschema.Properties{}
+	for _, subschema := range append([]*jsonschema.Schema{schema}, schema.AllOf...) {
+		subschema = g.resolveRef(subschema)
+		if len(subschema.AllOf) > 0 && subschema != schema {
+			subschema = g.mergeAllOf(subschema)
+		}
+		if subschema.Properties != nil {
+			for _, key := range subschema.Properties.Keys {
+				properties.Set(key, subschema.Properties.Schemas[key])
+			}
+		}
+		if subschema != schema {
+			merged.Required = append(merged.Required, subschema.Required...)
+		}
+	}
+	merged.Properties = properties
+	return merged
+}
+
+// variantName returns the name of the sum type field for the i-th variant.
+func variantName(variant *jsonschema.Schema, i int) string {
+	switch {
+	case variant.Ref != "":
+		return makeVarname(jsonschema.RefName(variant.Ref))
+	case variant.Title != "":
+		return makeVarname(variant.Title)
+	}
+	switch schemaKind(variant) {
+	case jsonschema.TypeObject:
+		return "Object"
+	case jsonschema.TypeArray:
+		return "Array"
+	case jsonschema.TypeString:
+		return "String"
+	case jsonschema.TypeInteger:
+		return "Int"
+	case jsonschema.TypeNumber:
+		return "Float64"
+	case jsonschema.TypeBoolean:
+		return "Bool"
+	default:
+		return fmt.Sprintf("Variant%d", i+1)
+	}
+}
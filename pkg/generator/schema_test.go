@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/jsonschema"
+)
+
+func TestSchemaFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "jsonschema"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			doc, err := jsonschema.Parse([]byte(readFile(filename)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := GenerateFromSchema(doc, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+			typeCheck(t, result.Code)
+		})
+	}
+}
+
+func TestSchemaUnsupportedRef(t *testing.T) {
+	doc, err := jsonschema.Parse([]byte(`{ "properties": { "a": { "$ref": "other.json#/$defs/A" } } }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateFromSchema(doc, Options{}); err == nil {
+		t.Errorf("expected an error for a ref to another document")
+	}
+}
+
+func TestSchemaInvalidOptions(t *testing.T) {
+	doc, err := jsonschema.Parse([]byte(`{ "properties": { "a": { "type": "string" } } }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []Options{{TypeName: "my-type"}, {PackageName: "1st"}} {
+		if _, err := GenerateFromSchema(doc, opts); err == nil {
+			t.Errorf("expected an error for options %+v", opts)
+		}
+	}
+}
+
+// typeCheck fails the test if the files of code, which form a single package, don't compile.
+func typeCheck(t *testing.T, code ...string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for i, fileCode := range code {
+		file, err := parser.ParseFile(fset, fmt.Sprintf("generated%d.go", i), fileCode, 0)
+		if err != nil {
+			t.Fatalf("generated code doesn't parse: %v", err)
+		}
+		files = append(files, file)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("generated", fset, files, nil); err != nil {
+		t.Errorf("generated code doesn't compile: %v", err)
+	}
+}
+
+func TestJSONSchemaFormatFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "to_jsonschema"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			result, err := GenerateFromString(readFile(filename), Options{Format: FormatJSONSchema})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+
+			// The inferred schema has to be usable as input again.
+			doc, err := jsonschema.Parse([]byte(result.Code))
+			if err != nil {
+				t.Fatal(err)
+			}
+			roundTripped, err := GenerateFromSchema(doc, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			typeCheck(t, roundTripped.Code)
+		})
+	}
+}
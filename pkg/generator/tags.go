@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Tag configures a struct tag of the generated fields, e.g. `db:"created_at"`. Its value is computed from the
+// key of the field.
+type Tag struct {
+	// Name is the key of the tag, e.g. "yaml", "bson" or "db". A Tag named "json" configures the json tag,
+	// which is always generated.
+	Name string
+	// Naming transforms the key into the value of the tag. The json tag has to keep the original key.
+	Naming Naming
+	// Modifiers are appended to the value, e.g. "omitempty" or "string".
+	Modifiers []string
+}
+
+// Naming transforms keys into tag values.
+type Naming int
+
+const (
+	// NamingOriginal keeps the key as is.
+	NamingOriginal Naming = iota
+	// NamingSnake converts the key to snake_case.
+	NamingSnake
+	// NamingCamel converts the key to camelCase.
+	NamingCamel
+)
+
+var namingNames = map[Naming]string{
+	NamingOriginal: "original",
+	NamingSnake:    "snake",
+	NamingCamel:    "camel",
+}
+
+func (n Naming) String() string {
+	if name, ok := namingNames[n]; ok {
+		return name
+	}
+	return fmt.Sprintf("Naming(%d)", int(n))
+}
+
+// ParseNaming returns the Naming for its name as used by the CLI, i.e. "original", "snake" or "camel".
+func ParseNaming(name string) (Naming, error) {
+	for naming, namingName := range namingNames {
+		if namingName == name {
+			return naming, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid naming %q. expected original, snake or camel", name)
+}
+
+// Apply transforms key according to n.
+func (n Naming) Apply(key string) string {
+	words := splitWords(key)
+	if n == NamingOriginal || len(words) == 0 {
+		return key
+	}
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if n == NamingCamel && i > 0 {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		words[i] = word
+	}
+	if n == NamingSnake {
+		return strings.Join(words, "_")
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits key into its words. Words are separated by anything but letters and digits, and by changes
+// of case, e.g. `HTTPStatusCode` consists of `HTTP`, `Status` and `Code`.
+func splitWords(key string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(r) {
+			previous := word[len(word)-1]
+			isNextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && isNextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// ParseTag parses a tag as written on the command line: the name of the tag, optionally followed by a naming and
+// modifiers, all separated by colons, e.g. `yaml`, `db:snake` or `bson:camel:omitempty`.
+func ParseTag(s string) (Tag, error) {
+	parts := strings.Split(s, ":")
+	tag := Tag{Name: parts[0]}
+	for _, part := range parts[1:] {
+		if naming, err := ParseNaming(part); err == nil {
+			tag.Naming = naming
+		} else {
+			tag.Modifiers = append(tag.Modifiers, part)
+		}
+	}
+	return tag, validateTag(tag)
+}
+
+func validateTags(tags []Tag) error {
+	for _, tag := range tags {
+		if err := validateTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTag(tag Tag) error {
+	if tag.Name == "" || strings.ContainsAny(tag.Name, " :\"`") {
+		return fmt.Errorf("invalid tag name %q", tag.Name)
+	}
+	if tag.Name == "validate" {
+		return errors.New("invalid tag name validate. its value holds rules rather than the key, use ValidateTags instead")
+	}
+	if tag.Name == "json" && tag.Naming != NamingOriginal {
+		return fmt.Errorf("invalid naming %v for the json tag. it has to match the original keys", tag.Naming)
+	}
+	for _, modifier := range tag.Modifiers {
+		if modifier == "" || strings.ContainsAny(modifier, " ,\"`") {
+			return fmt.Errorf("invalid modifier %q for tag %v", modifier, tag.Name)
+		}
+	}
+	return nil
+}
+
+// tagValue returns the value of tag for key, e.g. `created_at,omitempty`. options are further modifiers like
+// the omitempty of optional properties.
+func tagValue(tag Tag, key string, options []string) string {
+	value := tag.Naming.Apply(key)
+	var modifiers []string
+	for _, modifiersOfSource := range [][]string{options, tag.Modifiers} {
+		for _, modifier := range modifiersOfSource {
+			if !containsString(modifiers, modifier) {
+				modifiers = append(modifiers, modifier)
+				value += "," + modifier
+			}
+		}
+	}
+	return value
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNamingApply(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+	}{
+		{key: "name", snake: "name", camel: "name"},
+		{key: "createdAt", snake: "created_at", camel: "createdAt"},
+		{key: "user_id", snake: "user_id", camel: "userId"},
+		{key: "HTTPStatusCode", snake: "http_status_code", camel: "httpStatusCode"},
+		{key: "content-type", snake: "content_type", camel: "contentType"},
+		{key: "Address Line 2", snake: "address_line_2", camel: "addressLine2"},
+		{key: "gid_1", snake: "gid_1", camel: "gid1"},
+		{key: "Größe", snake: "größe", camel: "größe"},
+		{key: "$", snake: "$", camel: "$"},
+	}
+	for _, test := range tests {
+		if got := NamingOriginal.Apply(test.key); got != test.key {
+			t.Errorf("NamingOriginal.Apply(%q): got %v, want %v", test.key, got, test.key)
+		}
+		if got := NamingSnake.Apply(test.key); got != test.snake {
+			t.Errorf("NamingSnake.Apply(%q): got %v, want %v", test.key, got, test.snake)
+		}
+		if got := NamingCamel.Apply(test.key); got != test.camel {
+			t.Errorf("NamingCamel.Apply(%q): got %v, want %v", test.key, got, test.camel)
+		}
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Tag
+		wantErr bool
+	}{
+		{input: "yaml", want: Tag{Name: "yaml"}},
+		{input: "db:snake", want: Tag{Name: "db", Naming: NamingSnake}},
+		{input: "bson:camel:omitempty", want: Tag{Name: "bson", Naming: NamingCamel, Modifiers: []string{"omitempty"}}},
+		{input: "json:omitempty:string", want: Tag{Name: "json", Modifiers: []string{"omitempty", "string"}}},
+		{input: "json:snake", wantErr: true},
+		{input: ":snake", wantErr: true},
+		{input: "db:snake:", wantErr: true},
+		{input: "validate", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseTag(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseTag(%q): got error %v, want an error: %v", test.input, err, test.wantErr)
+			continue
+		}
+		if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseTag(%q): got %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestGenerateWithTags(t *testing.T) {
+	result, err := GenerateFromString(`{ "createdAt": "today" }`, Options{
+		Tags: []Tag{
+			{Name: "json", Modifiers: []string{"omitempty"}},
+			{Name: "db", Naming: NamingSnake},
+			{Name: "mapstructure", Naming: NamingOriginal, Modifiers: []string{"omitempty"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "`db:\"created_at\" json:\"createdAt,omitempty\" mapstructure:\"createdAt,omitempty\"`"
+	if !strings.Contains(result.Code, want) {
+		t.Errorf("GenerateFromString(): expected the tags %v, got\n%v", want, result.Code)
+	}
+
+	if _, err := GenerateFromString(`{}`, Options{Tags: []Tag{{Name: "db:x"}}}); err == nil {
+		t.Errorf("expected an error for an invalid tag name")
+	}
+	opts := Options{ValidateTags: true, Tags: []Tag{{Name: "validate"}}}
+	if _, err := GenerateFromString(`{ "id": 1 }`, opts); err == nil || !strings.Contains(err.Error(), "ValidateTags") {
+		t.Errorf("expected an error for a validate tag, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+)
+
+const typeScriptIndent = "  "
+
+// generateTypeScript renders root as a TypeScript declaration: an interface for objects and a type alias for
+// arrays. Nested objects are inlined just like the anonymous structs of the Go output. Keys that are missing
+// in some of the merged objects become optional properties, and values observed with different kinds become
+// union types.
+func generateTypeScript(root *model.Type, opts Options) (*Result, error) {
+	code := &strings.Builder{}
+	switch root.Kind {
+	case model.KindObject:
+		fmt.Fprintf(code, "export interface %v ", opts.TypeName)
+		writeTypeScriptObject(code, root, opts, 0)
+		code.WriteString("\n")
+	case model.KindArray:
+		fmt.Fprintf(code, "export type %v = ", opts.TypeName)
+		writeTypeScriptType(code, root, opts, 0)
+		code.WriteString(";\n")
+	default:
+		return nil, fmt.Errorf("invalid json. expected { or [ as initial node but received something else")
+	}
+	return &Result{Code: code.String()}, nil
+}
+
+func writeTypeScriptType(code *strings.Builder, t *model.Type, opts Options, depth int) {
+	switch t.Kind {
+	case model.KindObject:
+		writeTypeScriptObject(code, t, opts, depth)
+	case model.KindArray:
+		// Unions have to be wrapped in parentheses, e.g. `(string | number)[]`.
+		if t.Elem.Kind == model.KindUnion && len(typeScriptVariants(t.Elem)) > 1 {
+			code.WriteString("(")
+			writeTypeScriptType(code, t.Elem, opts, depth)
+			code.WriteString(")[]")
+		} else {
+			writeTypeScriptType(code, t.Elem, opts, depth)
+			code.WriteString("[]")
+		}
+	case model.KindString:
+		code.WriteString("string")
+	case model.KindInteger, model.KindFloat:
+		code.WriteString("number")
+	case model.KindBool:
+		code.WriteString("boolean")
+	case model.KindNull:
+		code.WriteString("null")
+	case model.KindUnion:
+		for i, variant := range typeScriptVariants(t) {
+			if i > 0 {
+				code.WriteString(" | ")
+			}
+			writeTypeScriptType(code, variant, opts, depth)
+		}
+	default:
+		code.WriteString("unknown")
+	}
+}
+
+// typeScriptVariants returns the variants of a union without the ones that TypeScript doesn't tell apart,
+// i.e. integers if floats were observed as well.
+func typeScriptVariants(union *model.Type) []*model.Type {
+	var variants []*model.Type
+	for _, variant := range union.Variants {
+		if variant.Kind == model.KindInteger && union.Variant(model.KindFloat) != nil {
+			continue
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+func writeTypeScriptObject(code *strings.Builder, obj *model.Type, opts Options, depth int) {
+	if len(obj.Fields) == 0 {
+		code.WriteString("{}")
+		return
+	}
+
+	indent := strings.Repeat(typeScriptIndent, depth+1)
+	code.WriteString("{\n")
+	for _, field := range orderFields(obj, opts.FieldOrder) {
+		code.WriteString(indent)
+		code.WriteString(makeTypeScriptPropertyName(field.Key))
+		if field.Optional {
+			code.WriteString("?")
+		}
+		code.WriteString(": ")
+		writeTypeScriptType(code, field.Type, opts, depth+1)
+		code.WriteString(";\n")
+	}
+	code.WriteString(strings.Repeat(typeScriptIndent, depth))
+	code.WriteString("}")
+}
+
+// makeTypeScriptPropertyName returns key as is if it's a valid identifier, and as a string literal otherwise.
+func makeTypeScriptPropertyName(key string) string {
+	for i, r := range key {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			quoted, _ := json.Marshal(key)
+			return string(quoted)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+)
+
+func TestTypeScriptFormatFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "to_typescript"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			result, err := GenerateFromString(readFile(filename), Options{Format: FormatTypeScript})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+		})
+	}
+}
+
+func TestMakeTypeScriptPropertyName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "name", want: "name"},
+		{key: "$schema", want: "$schema"},
+		{key: "_id2", want: "_id2"},
+		{key: "café", want: "café"},
+		{key: "2fa", want: `"2fa"`},
+		{key: "content-type", want: `"content-type"`},
+		{key: `say "hi"`, want: `"say \"hi\""`},
+		{key: "", want: `""`},
+	}
+	for _, test := range tests {
+		if got := makeTypeScriptPropertyName(test.key); got != test.want {
+			t.Errorf("makeTypeScriptPropertyName(%q): got %v, want %v", test.key, got, test.want)
+		}
+	}
+}
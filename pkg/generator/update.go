@@ -0,0 +1,405 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+	"github.com/marhaupe/json2struct/pkg/parse"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Update merges the types inferred from tree into existing, the source of a Go file declaring opts.TypeName,
+// usually generated earlier and edited by hand since. Keys that no field matches, counting the fields promoted
+// from embedded structs declared in existing, get new fields, and fields whose type doesn't fit the new values
+// are widened, e.g. from int to float64, or to interface{} if nothing else fits. Everything else stays as it is,
+// including renamed fields, tags, comments and fields missing from tree. Named types declared in existing are
+// updated as well. Only Go output supports it.
+func Update(existing string, tree parse.Node, opts Options) (*Result, error) {
+	return UpdateFromSamples(existing, []parse.Node{tree}, opts)
+}
+
+// UpdateFromSamples is like Update, but merges the types inferred from several samples of the same data.
+func UpdateFromSamples(existing string, samples []parse.Node, opts Options) (*Result, error) {
+	opts = opts.withDefaults()
+	if opts.Format != FormatGo {
+		return nil, fmt.Errorf("can't update existing code with format %v", opts.Format)
+	}
+	if err := validateTags(opts.Tags); err != nil {
+		return nil, err
+	}
+	if err := validateTypeOverrides(opts.TypeOverrides); err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "existing.go", existing, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the existing code: %v", err)
+	}
+	u := &updater{
+		src:     existing,
+		fset:    fset,
+		opts:    opts,
+		types:   make(map[string]*ast.TypeSpec),
+		visited: make(map[*ast.TypeSpec]bool),
+		imports: make(map[string]bool),
+		updated: make(map[ast.Node]bool),
+	}
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				u.types[typeSpec.Name.Name] = typeSpec
+			}
+		}
+	}
+	root, ok := u.types[opts.TypeName]
+	if !ok {
+		return nil, fmt.Errorf("the existing code doesn't declare the type %v", opts.TypeName)
+	}
+
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to update the types with")
+	}
+	shape := model.Infer(samples...)
+	if shape.Kind != model.KindObject && shape.Kind != model.KindArray {
+		return nil, fmt.Errorf("invalid json. expected { or [ as initial node but received something else")
+	}
+	if err := u.updateNamed(root, shape); err != nil {
+		return nil, err
+	}
+	u.addImports(file)
+
+	code, err := format.Source([]byte(u.apply()))
+	if err != nil {
+		return nil, fmt.Errorf("error formatting the updated code: %v", err)
+	}
+	return &Result{Code: string(code)}, nil
+}
+
+// edit replaces the source between two offsets.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// updater holds the state of a single run of Update. Instead of printing a modified syntax tree, it edits the
+// source, which keeps comments and formatting exactly as they were.
+type updater struct {
+	src     string
+	fset    *token.FileSet
+	opts    Options
+	types   map[string]*ast.TypeSpec
+	visited map[*ast.TypeSpec]bool
+	// imports are the import paths of the overridden types of added fields.
+	imports map[string]bool
+	edits   []edit
+	// updated holds the nodes that have already been replaced or updated. Fields promoted from an embedded struct
+	// are reached from every struct embedding it, but must only be edited once.
+	updated map[ast.Node]bool
+}
+
+func (u *updater) offset(pos token.Pos) int {
+	return u.fset.Position(pos).Offset
+}
+
+func (u *updater) replace(node ast.Node, text string) {
+	if u.updated[node] {
+		return
+	}
+	u.updated[node] = true
+	u.edits = append(u.edits, edit{start: u.offset(node.Pos()), end: u.offset(node.End()), text: text})
+}
+
+func (u *updater) insert(pos token.Pos, text string) {
+	u.edits = append(u.edits, edit{start: u.offset(pos), end: u.offset(pos), text: text})
+}
+
+// apply returns the source with all edits applied. Edits never overlap, since a replaced node isn't visited.
+func (u *updater) apply() string {
+	sort.SliceStable(u.edits, func(i, j int) bool {
+		return u.edits[i].start < u.edits[j].start
+	})
+	var b strings.Builder
+	last := 0
+	for _, e := range u.edits {
+		b.WriteString(u.src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(u.src[last:])
+	return b.String()
+}
+
+// updateNamed updates the declaration of a named type. Each declaration is only updated once, which also stops
+// recursive types.
+func (u *updater) updateNamed(spec *ast.TypeSpec, shape *model.Type) error {
+	if u.visited[spec] {
+		return nil
+	}
+	u.visited[spec] = true
+	if !u.fits(spec.Type, shape) {
+		return fmt.Errorf("the type %v doesn't fit the input", spec.Name.Name)
+	}
+	u.update(spec.Type, shape)
+	return nil
+}
+
+// update updates expr, which fits shape, with the fields shape has in addition.
+func (u *updater) update(expr ast.Expr, shape *model.Type) {
+	shape = shape.WithoutNull()
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		u.update(expr.X, shape)
+	case *ast.StarExpr:
+		u.update(expr.X, shape)
+	case *ast.Ident:
+		if spec, ok := u.types[expr.Name]; ok {
+			// The type fits, which was checked by fits.
+			_ = u.updateNamed(spec, shape)
+		}
+	case *ast.ArrayType:
+		if shape.Kind == model.KindArray {
+			u.updateField(expr.Elt, shape.Elem)
+		}
+	case *ast.StructType:
+		if shape.Kind == model.KindObject && !u.updated[expr] {
+			u.updated[expr] = true
+			u.updateStruct(expr, shape)
+		}
+	}
+}
+
+// updateField updates the type of a field or element, widening it if it doesn't fit shape.
+func (u *updater) updateField(expr ast.Expr, shape *model.Type) {
+	if u.fits(expr, shape) {
+		u.update(expr, shape)
+		return
+	}
+	u.replace(expr, u.widen(expr, shape))
+}
+
+// updateStruct adds fields for the keys of shape that s lacks, and updates the other fields.
+func (u *updater) updateStruct(s *ast.StructType, shape *model.Type) {
+	existing := make(map[string]*ast.Field)
+	usedNames := make(map[string]bool)
+	u.collectFields(s, existing, usedNames, make(map[*ast.TypeSpec]bool))
+
+	var added []jen.Code
+	for _, shapeField := range orderFields(shape, u.opts.FieldOrder) {
+		if field := lookupStructField(existing, shapeField.Key); field != nil {
+			// Overridden types are chosen by hand and never widened.
+			if _, ok := u.opts.TypeOverrides[shapeField.Key]; !ok {
+				u.updateField(field.Type, shapeField.Type)
+			}
+			continue
+		}
+		name := makeVarname(shapeField.Key)
+		for i := 2; usedNames[name]; i++ {
+			name = fmt.Sprintf("%v%d", makeVarname(shapeField.Key), i)
+		}
+		usedNames[name] = true
+		if override, ok := u.opts.TypeOverrides[shapeField.Key]; ok && importPathOf(override) != "" {
+			u.imports[importPathOf(override)] = true
+		}
+		g := &Generator{opts: u.opts}
+		tags := makeTagValues(u.opts, shapeField.Key)
+		added = append(added, jen.Id(name).Add(g.makeFieldType(shapeField)).Add(renderTag(tags, shapeField.Key)))
+	}
+	if len(added) > 0 {
+		// jen only renders fields as part of a struct, whose braces are dropped.
+		fields := jen.Struct(added...).GoString()
+		fields = fields[strings.Index(fields, "{")+1 : strings.LastIndex(fields, "}")]
+		if strings.HasSuffix(strings.TrimRight(u.src[:u.offset(s.Fields.Closing)], " \t"), "\n") {
+			fields = strings.TrimPrefix(fields, "\n")
+		}
+		u.insert(s.Fields.Closing, fields)
+	}
+}
+
+// collectFields records the fields of s by the key encoding/json matches them with, and the names they take up.
+// Like encoding/json, it includes the fields promoted from embedded structs, which are only known if they are
+// declared in the file, unless a shallower field has the same key. seen holds the embedded types already
+// followed.
+func (u *updater) collectFields(s *ast.StructType, existing map[string]*ast.Field, usedNames map[string]bool, seen map[*ast.TypeSpec]bool) {
+	var embedded []*ast.StructType
+	for _, field := range s.Fields.List {
+		if len(field.Names) > 0 {
+			for _, name := range field.Names {
+				usedNames[name.Name] = true
+				if key := jsonKeyOf(field, name.Name); existing[key] == nil {
+					existing[key] = field
+				}
+			}
+			continue
+		}
+
+		name := embeddedName(field.Type)
+		usedNames[name] = true
+		if key := jsonKeyOf(field, ""); key != "" {
+			// A tagged embedded struct isn't promoted, but a field of its own.
+			if existing[key] == nil {
+				existing[key] = field
+			}
+			continue
+		}
+		if spec, ok := u.types[name]; ok && !seen[spec] {
+			if embeddedStruct, ok := spec.Type.(*ast.StructType); ok {
+				seen[spec] = true
+				embedded = append(embedded, embeddedStruct)
+			}
+		}
+	}
+	// Promoted fields are collected after all fields of s, which take precedence.
+	for _, embeddedStruct := range embedded {
+		u.collectFields(embeddedStruct, existing, usedNames, seen)
+	}
+}
+
+// embeddedName returns the name of the field an embedded type declares, e.g. `Base` for `*pkg.Base`.
+func embeddedName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(expr.X)
+	case *ast.ParenExpr:
+		return embeddedName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	default:
+		return ""
+	}
+}
+
+// addImports imports the packages of the added fields that file doesn't import yet.
+func (u *updater) addImports(file *ast.File) {
+	for _, spec := range file.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+			delete(u.imports, path)
+		}
+	}
+	paths := make([]string, 0, len(u.imports))
+	for path := range u.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		u.insert(file.Name.End(), fmt.Sprintf("\n\nimport %q", path))
+	}
+}
+
+// lookupStructField returns the field for key. Like encoding/json, it prefers an exact match over a case
+// insensitive one.
+func lookupStructField(fields map[string]*ast.Field, key string) *ast.Field {
+	if field, ok := fields[key]; ok {
+		return field
+	}
+	for fieldKey, field := range fields {
+		if strings.EqualFold(fieldKey, key) {
+			return field
+		}
+	}
+	return nil
+}
+
+// jsonKeyOf returns the key encoding/json matches with the field called name.
+func jsonKeyOf(field *ast.Field, name string) string {
+	if field.Tag == nil {
+		return name
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return name
+	}
+	if key, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ","); key != "" {
+		return key
+	}
+	return name
+}
+
+// fits reports whether values of shape can be decoded into expr without widening it. Types that aren't declared
+// in the file, e.g. time.Time, are assumed to fit.
+func (u *updater) fits(expr ast.Expr, shape *model.Type) bool {
+	shape = shape.WithoutNull()
+	if shape.Kind == model.KindAny || shape.Kind == model.KindNull {
+		return true
+	}
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return u.fits(expr.X, shape)
+	case *ast.StarExpr:
+		return u.fits(expr.X, shape)
+	case *ast.InterfaceType, *ast.SelectorExpr, *ast.MapType:
+		return true
+	case *ast.StructType:
+		return shape.Kind == model.KindObject
+	case *ast.ArrayType:
+		return shape.Kind == model.KindArray
+	case *ast.Ident:
+		if spec, ok := u.types[expr.Name]; ok {
+			if u.visited[spec] {
+				return true
+			}
+			return u.fits(spec.Type, shape)
+		}
+		return identFits(expr.Name, shape)
+	default:
+		return true
+	}
+}
+
+// identFits reports whether values of shape can be decoded into the predeclared type name.
+func identFits(name string, shape *model.Type) bool {
+	switch name {
+	case "any":
+		return true
+	case "string":
+		return shape.Kind == model.KindString
+	case "bool":
+		return shape.Kind == model.KindBool
+	case "float32", "float64":
+		return shape.Kind == model.KindFloat || shape.Kind == model.KindInteger || isNumber(shape)
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return shape.Kind == model.KindInteger
+	default:
+		// Types declared in other files of the package are left alone.
+		return true
+	}
+}
+
+// widen returns the type replacing expr, which doesn't fit shape: float64 for integer types observed with
+// floats, and interface{} for anything else. Pointers keep pointing to the widened type, e.g. *int becomes
+// *float64, and the elements of slices are widened by update.
+func (u *updater) widen(expr ast.Expr, shape *model.Type) string {
+	shape = shape.WithoutNull()
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return u.widen(expr.X, shape)
+	case *ast.StarExpr:
+		if elem := u.widen(expr.X, shape); elem != "interface{}" {
+			return "*" + elem
+		}
+	case *ast.Ident:
+		if identFits("float64", shape) && identFits(expr.Name, &model.Type{Kind: model.KindInteger}) {
+			return "float64"
+		}
+	}
+	return "interface{}"
+}
+
+// isNumber reports whether shape is a union of integers and floats.
+func isNumber(shape *model.Type) bool {
+	return shape.Kind == model.KindUnion && len(shape.Variants) == 2 &&
+		shape.Variant(model.KindInteger) != nil && shape.Variant(model.KindFloat) != nil
+}
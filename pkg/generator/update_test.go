@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+// existingSuffix marks the existing code a sample in testdata/update is merged into.
+const existingSuffix = "_existing"
+
+func TestUpdateFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "update"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			tree, err := parse.ParseFromString(readFile(filename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			existing := readFile(filename + existingSuffix)
+			typeName := map[string]string{"users": "User", "orders": "Orders", "embedded": "Post", "measurement": "Measurement"}[path.Base(filename)]
+			result, err := Update(existing, tree, Options{TypeName: typeName, FieldOrder: FieldOrderSource})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+			typeCheck(t, result.Code)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// isRequired reports whether field was present with a non-zero value in every sample, which is what
+// `validate:"required"` checks. Since the zero values of primitives can't be told apart from missing keys,
+// primitives are only required if their values were kept and none of them was the zero value. Structs are
+// never required, as they can't be nil.
+func isRequired(field *model.Field) bool {
+	if field.Optional || field.Type.Nullable() {
+		return false
+	}
+	switch t := field.Type; t.Kind {
+	case model.KindString, model.KindInteger, model.KindFloat, model.KindBool:
+		return len(t.Values) > 0 && !t.Zero
+	case model.KindArray, model.KindUnion:
+		return true
+	default:
+		return false
+	}
+}
+
+// canBeZeroStruct reports whether field is an object whose struct may be the zero value, since its key may be
+// missing or null. Then none of its fields can be required, however often they were present in the object.
+func canBeZeroStruct(field *model.Field) bool {
+	return field.Type.WithoutNull().Kind == model.KindObject && (field.Optional || field.Type.Nullable())
+}
+
+// makeValidateTagValue returns the value of the validate tag of field, e.g. `required` or `required,dive` for
+// arrays of structs, whose elements the validator only checks when asked to dive into them. canBeZero reports
+// whether the struct holding field may be the zero value.
+func makeValidateTagValue(field *model.Field, canBeZero bool) string {
+	var rules []string
+	if isRequired(field) && !canBeZero {
+		rules = append(rules, "required")
+	}
+	if t := field.Type.WithoutNull(); t.Kind == model.KindArray && t.Elem.WithoutNull().Kind == model.KindObject {
+		rules = append(rules, "dive")
+	}
+	return strings.Join(rules, ",")
+}
+
+// makeValidateMethod returns the Validate method of the root type. It returns an error for the first value
+// that doesn't match the samples, naming it by its path, e.g. `items[2].status`.
+func (g *Generator) makeValidateMethod(root *model.Type) *jen.Statement {
+	receiver := makeReceiverName(g.opts.TypeName)
+	checks := g.makeValueChecks(root, func() *jen.Statement { return jen.Id(receiver) }, "", nil, false)
+	checks = append(checks, jen.Return(jen.Nil()))
+
+	return jen.Comment("Validate checks that the fields present in every sample are set, and that strings that only").Line().
+		Comment("had a few distinct values have one of them.").Line().
+		Func().Params(jen.Id(receiver).Id(g.opts.TypeName)).Id("Validate").Params().Error().Block(checks...)
+}
+
+// makeValueChecks returns the statements checking value, which is of type t. path is the format string naming
+// value in errors, and indices are its arguments, i.e. the indices of the enclosing arrays. canBeZero reports
+// whether value may be the zero value since the key, or that of an enclosing object, may be missing or null.
+func (g *Generator) makeValueChecks(t *model.Type, value func() *jen.Statement, path string, indices []jen.Code, canBeZero bool) []jen.Code {
+	var checks []jen.Code
+	switch t.Kind {
+	case model.KindObject:
+		for _, field := range orderFields(t, g.opts.FieldOrder) {
+			if g.isOverridden(field) {
+				continue
+			}
+			varname := makeVarname(field.Key)
+			fieldValue := func() *jen.Statement { return value().Dot(varname) }
+			fieldPath := joinValidatePath(path, field.Key)
+			fieldType := field.Type.WithoutNull()
+			if isRequired(field) && !canBeZero {
+				checks = append(checks, makeRequiredCheck(fieldType, fieldValue, fieldPath, indices))
+			}
+			fieldCanBeZero := canBeZero || !isRequired(field)
+			if fieldType.Kind == model.KindObject {
+				fieldCanBeZero = canBeZero || canBeZeroStruct(field)
+			}
+			checks = append(checks, g.makeValueChecks(fieldType, fieldValue, fieldPath, indices, fieldCanBeZero)...)
+		}
+	case model.KindArray:
+		elem := t.Elem.WithoutNull()
+		index, item := fmt.Sprintf("i%d", len(indices)), fmt.Sprintf("v%d", len(indices))
+		elemIndices := append(append([]jen.Code{}, indices...), jen.Id(index))
+		elemChecks := g.makeValueChecks(elem, func() *jen.Statement { return jen.Id(item) }, path+"[%d]", elemIndices, t.Elem.Nullable())
+		if len(elemChecks) > 0 {
+			checks = append(checks, jen.For(jen.List(jen.Id(index), jen.Id(item)).Op(":=").Range().Add(value())).Block(elemChecks...))
+		}
+	case model.KindString:
+		enum := t.Enum()
+		if enum == nil {
+			break
+		}
+		if canBeZero && !containsString(enum, "") {
+			enum = append(enum, "")
+		}
+		var cases []jen.Code
+		for _, value := range enum {
+			cases = append(cases, jen.Lit(value))
+		}
+		args := append(append([]jen.Code{jen.Lit(path + ": unexpected value %q")}, indices...), value())
+		checks = append(checks, jen.Switch(value()).Block(
+			jen.Case(cases...),
+			jen.Default().Return(jen.Qual("fmt", "Errorf").Call(args...)),
+		))
+	}
+	return checks
+}
+
+// makeRequiredCheck returns the statement checking that value, which is of type t, isn't the zero value.
+func makeRequiredCheck(t *model.Type, value func() *jen.Statement, path string, indices []jen.Code) jen.Code {
+	var isZero *jen.Statement
+	switch t.Kind {
+	case model.KindString:
+		isZero = value().Op("==").Lit("")
+	case model.KindInteger, model.KindFloat:
+		isZero = value().Op("==").Lit(0)
+	case model.KindBool:
+		isZero = jen.Op("!").Add(value())
+	default:
+		isZero = value().Op("==").Nil()
+	}
+	args := append([]jen.Code{jen.Lit(path + " is required")}, indices...)
+	return jen.If(isZero).Block(jen.Return(jen.Qual("fmt", "Errorf").Call(args...)))
+}
+
+// joinValidatePath appends key to path, escaping it for use in a format string.
+func joinValidatePath(path, key string) string {
+	key = strings.ReplaceAll(key, "%", "%%")
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// makeReceiverName returns the conventional receiver name for typeName, i.e. its lower cased first letter.
+func makeReceiverName(typeName string) string {
+	for _, r := range typeName {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToLower(r))
+		}
+	}
+	return "t"
+}
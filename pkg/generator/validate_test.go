@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"os/exec"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+)
+
+func TestValidateFiles(t *testing.T) {
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "validate"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			result, err := GenerateFromString(readFile(filename), Options{ValidateTags: true, ValidateMethod: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := readFile(filename + expectedSuffix)
+			if result.Code != expected {
+				t.Errorf("Test failed. \nFilename: %v \nDiff: \n\n%v", filename, diff.Diff(result.Code, expected))
+			}
+			typeCheck(t, result.Code)
+		})
+	}
+}
+
+func TestValidateAcceptsSamples(t *testing.T) {
+	if testing.Short() {
+		t.Skip("running go test is slow")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+
+	inputFiles, err := listValidInputFiles(path.Join(dirName, "validate"))
+	if err != nil {
+		t.Fatal("Error reading input files", err)
+	}
+	for _, filename := range inputFiles {
+		t.Run(path.Base(filename), func(t *testing.T) {
+			sample := readFile(filename)
+			result, err := GenerateFromString(sample, Options{ValidateTags: true, ValidateMethod: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "go.mod"), "module generated\n")
+			writeFile(t, filepath.Join(dir, "generated.go"), result.Code)
+			writeFile(t, filepath.Join(dir, "sample.json"), sample)
+			writeFile(t, filepath.Join(dir, "generated_test.go"), `package generated
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	data, err := os.ReadFile("sample.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value JSONToStruct
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatal(err)
+	}
+	if err := value.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+`)
+			cmd := exec.Command("go", "test", ".")
+			cmd.Dir = dir
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("Validate() rejected the sample it was generated from: %s", output)
+			}
+		})
+	}
+}
+
+func TestMakeReceiverName(t *testing.T) {
+	tests := []struct {
+		typeName string
+		want     string
+	}{
+		{typeName: "JSONToStruct", want: "j"},
+		{typeName: "_User", want: "u"},
+		{typeName: "_1", want: "t"},
+	}
+	for _, test := range tests {
+		if got := makeReceiverName(test.typeName); got != test.want {
+			t.Errorf("makeReceiverName(%q): got %v, want %v", test.typeName, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package jsontag holds what the generator and the verifier share about the json tags of encoding/json.
+package jsontag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsValidName mirrors the tag name validation of encoding/json. Fields with an invalid name are matched by
+// their field name instead.
+func IsValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return true
+}
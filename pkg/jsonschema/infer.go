@@ -0,0 +1,109 @@
+package jsonschema
+
+import (
+	"sort"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+)
+
+// Draft202012 is the `$schema` of inferred schemas.
+const Draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// FromModel returns a schema that describes t. Keys are required if they are present in every object that was
+// merged into a property, e.g. all objects of an array. Values observed as null make the type nullable, and
+// integers are widened to numbers if floats were observed for the same value.
+func FromModel(t *model.Type) *Schema {
+	variants := []*model.Type{t}
+	if t.Kind == model.KindUnion {
+		variants = t.Variants
+	}
+
+	var types TypeList
+	var schemas []*Schema
+	isStructured := false
+	for _, variant := range variants {
+		var schema *Schema
+		switch variant.Kind {
+		case model.KindAny:
+			return &Schema{}
+		case model.KindObject:
+			schema = fromObject(variant)
+			isStructured = true
+		case model.KindArray:
+			schema = &Schema{Type: TypeList{TypeArray}}
+			if variant.Elem.Kind != model.KindAny {
+				schema.Items = FromModel(variant.Elem)
+			}
+			isStructured = true
+		case model.KindFloat:
+			schema = &Schema{Type: TypeList{TypeNumber}}
+		case model.KindInteger:
+			if t.Variant(model.KindFloat) != nil {
+				continue
+			}
+			schema = &Schema{Type: TypeList{TypeInteger}}
+		default:
+			schema = &Schema{Type: TypeList{typeNames[variant.Kind]}}
+		}
+		types = append(types, schema.Type...)
+		schemas = append(schemas, schema)
+	}
+
+	switch {
+	case len(types.WithoutNull()) <= 1:
+		// A single type, optionally nullable, e.g. `"type": ["string", "null"]`.
+		schema := schemas[0]
+		schema.Type = types
+		return schema
+	case !isStructured:
+		return &Schema{Type: types}
+	default:
+		return &Schema{AnyOf: schemas}
+	}
+}
+
+var typeNames = map[model.Kind]string{
+	model.KindString: TypeString,
+	model.KindBool:   TypeBoolean,
+	model.KindNull:   TypeNull,
+}
+
+// fromObject returns the schema of an object type. A key is required unless its field is optional.
+func fromObject(t *model.Type) *Schema {
+	properties := &Properties{}
+	schema := &Schema{Type: TypeList{TypeObject}, Properties: properties}
+	for _, field := range t.Fields {
+		properties.Set(field.Key, FromModel(field.Type))
+		if !field.Optional {
+			schema.Required = append(schema.Required, field.Key)
+		}
+	}
+	return schema
+}
+
+// SortProperties sorts the properties of s and all of its subschemas alphabetically.
+func (s *Schema) SortProperties() {
+	if s == nil {
+		return
+	}
+	if s.Properties != nil {
+		sort.Strings(s.Properties.Keys)
+		sort.Strings(s.Required)
+		for _, property := range s.Properties.Schemas {
+			property.SortProperties()
+		}
+	}
+	s.AdditionalProperties.SortProperties()
+	s.Items.SortProperties()
+	s.Not.SortProperties()
+	for _, subschemas := range [][]*Schema{s.PrefixItems, s.OneOf, s.AnyOf, s.AllOf} {
+		for _, subschema := range subschemas {
+			subschema.SortProperties()
+		}
+	}
+	for _, defs := range []map[string]*Schema{s.Defs, s.Definitions} {
+		for _, def := range defs {
+			def.SortProperties()
+		}
+	}
+}
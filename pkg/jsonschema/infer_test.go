@@ -0,0 +1,63 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+func TestFromModel(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "primitives",
+			json: `{ "s": "a", "i": 1, "f": 1.5, "b": true, "n": null }`,
+			want: `{"type":"object","properties":{"s":{"type":"string"},"i":{"type":"integer"},"f":{"type":"number"},"b":{"type":"boolean"},"n":{"type":"null"}},"required":["s","i","f","b","n"]}`,
+		},
+		{
+			name: "required keys based on presence",
+			json: `[{ "id": 1, "name": "a" }, { "id": 2 }]`,
+			want: `{"type":"array","items":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id"]}}`,
+		},
+		{
+			name: "nullable and widened values",
+			json: `[{ "a": "x", "b": 1 }, { "a": null, "b": 1.5 }]`,
+			want: `{"type":"array","items":{"type":"object","properties":{"a":{"type":["string","null"]},"b":{"type":"number"}},"required":["a","b"]}}`,
+		},
+		{
+			name: "mixed primitives",
+			json: `["a", 1, null]`,
+			want: `{"type":"array","items":{"type":["string","integer","null"]}}`,
+		},
+		{
+			name: "objects mixed with primitives",
+			json: `[{ "a": 1 }, "b"]`,
+			want: `{"type":"array","items":{"anyOf":[{"type":"object","properties":{"a":{"type":"integer"}},"required":["a"]},{"type":"string"}]}}`,
+		},
+		{
+			name: "nested arrays and empty arrays",
+			json: `{ "matrix": [[1, 2], [3]], "empty": [] }`,
+			want: `{"type":"object","properties":{"matrix":{"type":"array","items":{"type":"array","items":{"type":"integer"}}},"empty":{"type":"array"}},"required":["matrix","empty"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := parse.ParseFromString(tt.json)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(FromModel(model.Infer(tree)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("FromModel(): \ngot:\n %s \nwant:\n %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Package jsonschema contains a model of JSON Schema documents (draft-07 and draft 2020-12) that is good enough
+// to generate types from, together with the resolution of local `$ref`s.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is a single (sub)schema. Keywords that don't affect the shape of the described data are omitted.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type   TypeList `json:"type,omitempty"`
+	Format string   `json:"format,omitempty"`
+	Enum   []any    `json:"enum,omitempty"`
+	Const  any      `json:"const,omitempty"`
+	// Nullable is the OpenAPI 3.0 way of allowing null, which JSON Schema expresses through Type.
+	Nullable bool `json:"nullable,omitempty"`
+
+	Properties           *Properties `json:"properties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties *Schema     `json:"additionalProperties,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+	// PrefixItems describes tuples. The draft-07 form of `items` with an array of schemas is read into it as well.
+	PrefixItems []*Schema `json:"prefixItems,omitempty"`
+
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	AllOf []*Schema `json:"allOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+}
+
+// schemaAlias has the fields of Schema but none of its methods, which lets UnmarshalJSON fall back to the
+// default decoding.
+type schemaAlias Schema
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	// The boolean schema `true` allows everything, `false` allows nothing.
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		*s = Schema{}
+		return nil
+	case "false":
+		*s = Schema{Not: &Schema{}}
+		return nil
+	}
+
+	var raw struct {
+		*schemaAlias
+		Items json.RawMessage `json:"items,omitempty"`
+	}
+	raw.schemaAlias = (*schemaAlias)(s)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	items := bytes.TrimSpace(raw.Items)
+	switch {
+	case len(items) == 0:
+	case items[0] == '[':
+		return json.Unmarshal(items, &s.PrefixItems)
+	default:
+		s.Items = &Schema{}
+		return json.Unmarshal(items, s.Items)
+	}
+	return nil
+}
+
+// IsRequired reports whether key is listed in the required keywords of s.
+func (s *Schema) IsRequired(key string) bool {
+	for _, required := range s.Required {
+		if required == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNullable reports whether s allows null, either through its type or the OpenAPI nullable keyword.
+func (s *Schema) IsNullable() bool {
+	return s.Nullable || s.Type.Contains(TypeNull)
+}
+
+// The primitive types of JSON Schema.
+const (
+	TypeNull    = "null"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeNumber  = "number"
+	TypeString  = "string"
+	TypeInteger = "integer"
+)
+
+// TypeList is the value of the `type` keyword, which is either a single type or a list of types.
+type TypeList []string
+
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings: %v", err)
+	}
+	*t = list
+	return nil
+}
+
+func (t TypeList) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// Contains reports whether typ is one of the types of t.
+func (t TypeList) Contains(typ string) bool {
+	for _, candidate := range t {
+		if candidate == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutNull returns the types of t except for null.
+func (t TypeList) WithoutNull() TypeList {
+	var types TypeList
+	for _, typ := range t {
+		if typ != TypeNull {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// Properties are the properties of an object schema. Unlike a map, they remember the order of their keys.
+type Properties struct {
+	// Keys lists the property names in the order they appear in the document.
+	Keys    []string
+	Schemas map[string]*Schema
+}
+
+// Set adds or replaces the schema of a property.
+func (p *Properties) Set(key string, schema *Schema) {
+	if p.Schemas == nil {
+		p.Schemas = make(map[string]*Schema)
+	}
+	if _, ok := p.Schemas[key]; !ok {
+		p.Keys = append(p.Keys, key)
+	}
+	p.Schemas[key] = schema
+}
+
+// Len returns the number of properties. It's safe to call on nil.
+func (p *Properties) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.Keys)
+}
+
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		schema := &Schema{}
+		if err := dec.Decode(schema); err != nil {
+			return fmt.Errorf("property %q: %v", key, err)
+		}
+		p.Set(key, schema)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func (p Properties) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, key := range p.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedSchema, err := json.Marshal(p.Schemas[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedSchema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Document is a parsed JSON Schema file. It resolves the `$ref`s pointing into the same file.
+type Document struct {
+	// Root is the schema the document consists of. It's nil for documents that aren't a schema themselves but
+	// only contain named schemas, see ParseDefinitions.
+	Root *Schema
+
+	raw         any
+	refs        map[string]*Schema
+	definitions []string
+}
+
+// Parse parses a JSON Schema document.
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{
+		Root: &Schema{},
+		refs: make(map[string]*Schema),
+	}
+	if err := json.Unmarshal(data, doc.Root); err != nil {
+		return nil, fmt.Errorf("error parsing schema: %v", err)
+	}
+	if err := json.Unmarshal(data, &doc.raw); err != nil {
+		return nil, fmt.Errorf("error parsing schema: %v", err)
+	}
+	for name := range doc.Root.Defs {
+		doc.definitions = append(doc.definitions, "#/$defs/"+escapePointerToken(name))
+	}
+	for name := range doc.Root.Definitions {
+		doc.definitions = append(doc.definitions, "#/definitions/"+escapePointerToken(name))
+	}
+	sort.Strings(doc.definitions)
+	return doc, nil
+}
+
+// ParseDefinitions parses a document that isn't a schema itself, but contains named schemas in the object
+// that pointer points to, e.g. `#/components/schemas` of an OpenAPI document. The document may be missing
+// that object entirely.
+func ParseDefinitions(data []byte, pointer string) (*Document, error) {
+	doc := &Document{
+		refs: make(map[string]*Schema),
+	}
+	if err := json.Unmarshal(data, &doc.raw); err != nil {
+		return nil, fmt.Errorf("error parsing document: %v", err)
+	}
+	definitions, err := doc.lookup(pointer)
+	if err != nil {
+		return doc, nil
+	}
+	schemas, ok := definitions.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid document: %v must be an object", pointer)
+	}
+	for name := range schemas {
+		doc.definitions = append(doc.definitions, pointer+"/"+escapePointerToken(name))
+	}
+	sort.Strings(doc.definitions)
+	return doc, nil
+}
+
+// Definitions returns the refs of all named schemas of the document, sorted alphabetically. For a JSON Schema,
+// these are the schemas declared in `$defs` or `definitions` of the root schema.
+func (d *Document) Definitions() []string {
+	return d.definitions
+}
+
+// Resolve returns the schema that ref points to. Only refs within the document are supported, i.e. refs
+// consisting of a JSON Pointer fragment like `#/$defs/Pet`.
+func (d *Document) Resolve(ref string) (*Schema, error) {
+	if schema, ok := d.refs[ref]; ok {
+		return schema, nil
+	}
+	if ref == "#" && d.Root != nil {
+		return d.Root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q. only refs within the same document are supported", ref)
+	}
+
+	value, err := d.lookup(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid $ref %q: %v", ref, err)
+	}
+
+	// Round-tripping the referenced value is the simplest way to apply the custom decoding of Schema.
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("invalid $ref %q: %v", ref, err)
+	}
+	d.refs[ref] = schema
+	return schema, nil
+}
+
+// lookup returns the raw value that the JSON Pointer fragment points to.
+func (d *Document) lookup(pointer string) (any, error) {
+	value := d.raw
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "#/"), "/") {
+		token, err := unescapePointerToken(token)
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			value = v[token]
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("no element %v", token)
+			}
+			value = v[index]
+		default:
+			value = nil
+		}
+		if value == nil {
+			return nil, fmt.Errorf("%v not found", token)
+		}
+	}
+	return value, nil
+}
+
+// RefName returns the last token of ref, e.g. `Pet` for `#/$defs/Pet`. It's the natural name for the type
+// generated for the referenced schema.
+func RefName(ref string) string {
+	token := ref[strings.LastIndex(ref, "/")+1:]
+	if name, err := unescapePointerToken(token); err == nil {
+		return name
+	}
+	return token
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func unescapePointerToken(token string) (string, error) {
+	// The pointer is part of a URI fragment, so it may be percent-encoded.
+	token, err := url.PathUnescape(token)
+	if err != nil {
+		return "", err
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token), nil
+}
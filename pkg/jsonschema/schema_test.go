@@ -0,0 +1,101 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(`{
+		"type": "object",
+		"properties": {
+			"b": { "type": ["string", "null"] },
+			"a": { "type": "array", "items": { "type": "integer" } },
+			"tuple": { "type": "array", "items": [{ "type": "string" }, true] },
+			"never": false
+		},
+		"required": ["a"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := doc.Root
+	if want := []string{"b", "a", "tuple", "never"}; !reflect.DeepEqual(root.Properties.Keys, want) {
+		t.Errorf("Parse(): got property order %v, want %v", root.Properties.Keys, want)
+	}
+	if !root.IsRequired("a") || root.IsRequired("b") {
+		t.Errorf("Parse(): got required %v, want [a]", root.Required)
+	}
+
+	b := root.Properties.Schemas["b"]
+	if !b.IsNullable() || !reflect.DeepEqual(b.Type.WithoutNull(), TypeList{TypeString}) {
+		t.Errorf("Parse(): got type %v for b, want a nullable string", b.Type)
+	}
+	if a := root.Properties.Schemas["a"]; a.Items == nil || !a.Items.Type.Contains(TypeInteger) {
+		t.Errorf("Parse(): got items %#v for a, want an integer schema", a.Items)
+	}
+	if tuple := root.Properties.Schemas["tuple"]; tuple.Items != nil || len(tuple.PrefixItems) != 2 {
+		t.Errorf("Parse(): got items %#v and prefix items %#v for tuple, want two prefix items", tuple.Items, tuple.PrefixItems)
+	}
+	if never := root.Properties.Schemas["never"]; never.Not == nil {
+		t.Errorf("Parse(): the boolean schema false should allow nothing, got %#v", never)
+	}
+}
+
+func TestMarshalKeepsPropertyOrder(t *testing.T) {
+	const input = `{"type":"object","properties":{"z":{"type":"string"},"a":{"type":["integer","null"]}}}`
+	doc, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := json.Marshal(doc.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != input {
+		t.Errorf("json.Marshal(): got %s, want %s", output, input)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	doc, err := Parse([]byte(`{
+		"$defs": { "a/b": { "type": "string" } },
+		"definitions": { "Pet": { "type": "object" } },
+		"properties": { "list": { "items": [{ "type": "boolean" }] } }
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "#/$defs/a~1b", want: TypeString},
+		{ref: "#/definitions/Pet", want: TypeObject},
+		{ref: "#/properties/list/items/0", want: TypeBoolean},
+		{ref: "#/$defs/missing", wantErr: true},
+		{ref: "other.json#/$defs/Pet", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			schema, err := doc.Resolve(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(): got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !schema.Type.Contains(tt.want) {
+				t.Errorf("Resolve(): got type %v, want %v", schema.Type, tt.want)
+			}
+		})
+	}
+
+	if want := []string{"#/$defs/a~1b", "#/definitions/Pet"}; !reflect.DeepEqual(doc.Definitions(), want) {
+		t.Errorf("Definitions(): got %v, want %v", doc.Definitions(), want)
+	}
+	if name := RefName("#/$defs/a~1b"); name != "a/b" {
+		t.Errorf("RefName(): got %v, want a/b", name)
+	}
+}
@@ -0,0 +1,107 @@
+package lex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// unescape decodes the escape sequences of a raw string lexem, i.e. the text between the quotes.
+// Strict mode accepts the escapes defined by RFC 8259. Lenient mode additionally accepts the JSON5 escapes
+// `\'`, `\v`, `\0`, `\xHH`, line continuations and an escaped character standing for itself.
+func (l *Lexer) unescape(raw string) string {
+	// Most strings don't contain any escapes, so avoid allocating in that case.
+	if strings.IndexByte(raw, backslash) == -1 {
+		return raw
+	}
+
+	var b strings.Builder
+	b.Grow(len(raw))
+	for i := 0; i < len(raw); {
+		c := raw[i]
+		if c != backslash {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		if i+1 >= len(raw) {
+			panic("unterminated escape sequence")
+		}
+
+		r, width := utf8.DecodeRuneInString(raw[i+1:])
+		i += 1 + width
+		switch r {
+		case '"', '\\', '/':
+			b.WriteRune(r)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			r1 := decodeHex(raw, i, 4)
+			i += 4
+			if utf16.IsSurrogate(r1) {
+				// A high surrogate has to be followed by an escaped low surrogate to form a valid code point.
+				// Just like encoding/json, lone surrogates are replaced by the replacement character.
+				r2 := utf8.RuneError
+				if strings.HasPrefix(raw[i:], `\u`) && i+6 <= len(raw) {
+					r2 = utf16.DecodeRune(r1, decodeHex(raw, i+2, 4))
+				}
+				if r2 != utf8.RuneError {
+					i += 6
+				}
+				r1 = r2
+			}
+			b.WriteRune(r1)
+		default:
+			if !l.lenient {
+				panic(fmt.Sprintf("invalid escape sequence \\%v", string(r)))
+			}
+			i = unescapeLenient(&b, raw, i, r)
+		}
+	}
+	return b.String()
+}
+
+// unescapeLenient writes the character denoted by the JSON5 escape `\` + r to b and returns the position
+// after the escape sequence.
+func unescapeLenient(b *strings.Builder, raw string, i int, r rune) int {
+	switch r {
+	case 'v':
+		b.WriteByte('\v')
+	case '0':
+		b.WriteByte(0)
+	case 'x':
+		b.WriteRune(decodeHex(raw, i, 2))
+		return i + 2
+	case carriageReturn:
+		// A line continuation may use \r\n, both of which are dropped.
+		if strings.HasPrefix(raw[i:], string(newline)) {
+			return i + 1
+		}
+	case newline, '\u2028', '\u2029':
+		// Line continuations don't contribute to the string value.
+	default:
+		b.WriteRune(r)
+	}
+	return i
+}
+
+func decodeHex(raw string, start, digits int) rune {
+	if start+digits > len(raw) {
+		panic(fmt.Sprintf("invalid escape sequence %v", raw[start-2:]))
+	}
+	value, err := strconv.ParseUint(raw[start:start+digits], 16, 32)
+	if err != nil {
+		panic(fmt.Sprintf("invalid escape sequence %v", raw[start-2:start+digits]))
+	}
+	return rune(value)
+}
@@ -3,6 +3,7 @@ package lex
 import (
 	"fmt"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -20,6 +21,8 @@ const (
 	ItemInteger
 	ItemFloat
 	ItemNil
+	// ItemIdentifier is an unquoted word other than a keyword, which is only valid as a key in lenient mode.
+	ItemIdentifier
 	ItemLeftBrace
 	ItemRightBrace
 	ItemLeftSqrBrace
@@ -34,6 +37,8 @@ const (
 	whitespace          = ' '
 	tab                 = '\t'
 	newline             = '\n'
+	carriageReturn      = '\r'
+	byteOrderMark       = "\uFEFF"
 	comma               = ','
 	colon               = ':'
 	leftBrace           = '{'
@@ -41,6 +46,12 @@ const (
 	leftSqrBrace        = '['
 	rightSqrBrace       = ']'
 	quote               = '"'
+	singleQuote         = '\''
+	slash               = '/'
+	asterisk            = '*'
+	dot                 = '.'
+	dollar              = '$'
+	underscore          = '_'
 	plus                = '+'
 	minus               = '-'
 	zero                = '0'
@@ -52,19 +63,32 @@ const (
 	validNumberPrefixes = "+-"
 	validNumberDigits   = "0123456789"
 	validNumberSuffixes = ".eE+-0123456789"
+	validHexDigits      = "0123456789abcdefABCDEF"
+	hexPrefixes         = "xX"
 	wordTrue            = "true"
 	wordFalse           = "false"
-	wordNullLength      = len("null")
+	wordNull            = "null"
+	wordNullLength      = len(wordNull)
+	wordNaN             = "NaN"
+	wordInfinity        = "Infinity"
 )
 
 const EOF = -1
 
 type Lexer struct {
-	input string
-	pos   int
-	start int
-	width int
-	state stateFn
+	input   string
+	pos     int
+	start   int
+	width   int
+	state   stateFn
+	lenient bool
+}
+
+// Options configures optional behavior of the Lexer.
+type Options struct {
+	// Lenient makes the Lexer accept JSONC and JSON5 syntax in addition to plain JSON:
+	// comments, unquoted keys, single-quoted strings, hexadecimal numbers and NaN/Infinity.
+	Lenient bool
 }
 
 func (l *Lexer) NextItem() *Item {
@@ -78,12 +102,22 @@ func (l *Lexer) NextItem() *Item {
 }
 
 func Lex(json string) *Lexer {
+	return LexWithOptions(json, Options{})
+}
+
+func LexWithOptions(json string, opts Options) *Lexer {
 	l := &Lexer{
-		input: json,
-		pos:   0,
-		start: 0,
-		width: 0,
-		state: lexWhitespace,
+		input:   json,
+		pos:     0,
+		start:   0,
+		width:   0,
+		state:   lexWhitespace,
+		lenient: opts.Lenient,
+	}
+	// A leading UTF-8 byte order mark isn't valid JSON, but many editors on Windows write one anyway.
+	if strings.HasPrefix(json, byteOrderMark) {
+		l.pos = len(byteOrderMark)
+		l.ignore()
 	}
 	return l
 }
@@ -116,6 +150,12 @@ func (l *Lexer) acceptRun(valid string) int {
 	return validCount
 }
 
+func (l *Lexer) peek() rune {
+	r := l.next()
+	l.backup()
+	return r
+}
+
 func (l *Lexer) backup() {
 	l.pos -= l.width
 }
@@ -160,7 +200,7 @@ func (l *Lexer) ignore() {
 type stateFn func(l *Lexer) (stateFn, *Item)
 
 func lexWhitespace(l *Lexer) (stateFn, *Item) {
-	for r := l.next(); isSpace(r) || r == newline; r = l.next() {
+	for r := l.next(); l.isWhitespace(r); r = l.next() {
 	}
 	l.backup()
 	l.ignore()
@@ -169,6 +209,8 @@ func lexWhitespace(l *Lexer) (stateFn, *Item) {
 	case r == EOF:
 		item := l.emit(ItemEOF)
 		return nil, item
+	case l.lenient && r == slash:
+		return lexComment, nil
 	case r == leftBrace:
 		return lexLeftBrace, nil
 	case r == leftSqrBrace:
@@ -181,11 +223,14 @@ func lexWhitespace(l *Lexer) (stateFn, *Item) {
 		return lexColon, nil
 	case r == comma:
 		return lexComma, nil
-	case r == quote:
+	case r == quote || (l.lenient && r == singleQuote):
 		return lexString, nil
-	case isNumber(r):
+	case isNumber(r) || (l.lenient && r == dot):
 		l.backup()
 		return lexNumber, nil
+	case l.lenient && isIdentifierStart(r):
+		l.backup()
+		return lexIdentifier, nil
 	case isNull(r):
 		l.backup()
 		return lexNull, nil
@@ -197,6 +242,52 @@ func lexWhitespace(l *Lexer) (stateFn, *Item) {
 	}
 }
 
+// lexComment skips a `// line` or `/* block */` comment. Comments are only accepted in lenient mode.
+func lexComment(l *Lexer) (stateFn, *Item) {
+	switch l.next() {
+	case slash:
+		// Lines may also end with a lone carriage return.
+		for r := l.next(); r != newline && r != carriageReturn && r != EOF; r = l.next() {
+		}
+	case asterisk:
+		for {
+			r := l.next()
+			if r == EOF {
+				panic("unterminated block comment")
+			}
+			if r == asterisk && l.peek() == slash {
+				l.next()
+				break
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unexpected character %v", string(l.input[l.start:l.pos])))
+	}
+	l.ignore()
+	return lexWhitespace, nil
+}
+
+// lexIdentifier lexes an unquoted word in lenient mode. Keywords keep their usual item type,
+// every other word is emitted as an identifier, which the parser only accepts as an unquoted key.
+func lexIdentifier(l *Lexer) (stateFn, *Item) {
+	for r := l.next(); isIdentifierPart(r); r = l.next() {
+	}
+	l.backup()
+
+	var item *Item
+	switch l.input[l.start:l.pos] {
+	case wordTrue, wordFalse:
+		item = l.emit(ItemBool)
+	case wordNull:
+		item = l.emit(ItemNil)
+	case wordNaN, wordInfinity:
+		item = l.emit(ItemFloat)
+	default:
+		item = l.emit(ItemIdentifier)
+	}
+	return lexWhitespace, item
+}
+
 func lexNull(l *Lexer) (stateFn, *Item) {
 	l.pos += wordNullLength
 	item := l.emit(ItemNil)
@@ -221,6 +312,11 @@ func lexBool(l *Lexer) (stateFn, *Item) {
 
 func lexNumber(l *Lexer) (stateFn, *Item) {
 	l.acceptRun(validNumberPrefixes)
+	if l.lenient {
+		if item := l.lexLenientNumber(); item != nil {
+			return lexWhitespace, item
+		}
+	}
 	l.acceptRun(validNumberDigits)
 
 	count := l.acceptRun(validNumberSuffixes)
@@ -234,11 +330,33 @@ func lexNumber(l *Lexer) (stateFn, *Item) {
 	return lexWhitespace, item
 }
 
+// lexLenientNumber lexes the JSON5 number forms that plain JSON doesn't know about, i.e. signed
+// Infinity, NaN and hexadecimal integers. It returns nil without consuming any input otherwise.
+func (l *Lexer) lexLenientNumber() *Item {
+	rest := l.input[l.pos:]
+	switch {
+	case strings.HasPrefix(rest, wordInfinity):
+		l.pos += len(wordInfinity)
+		return l.emit(ItemFloat)
+	case strings.HasPrefix(rest, wordNaN):
+		l.pos += len(wordNaN)
+		return l.emit(ItemFloat)
+	case len(rest) > 2 && rest[0] == zero && strings.ContainsRune(hexPrefixes, rune(rest[1])):
+		l.pos += 2
+		if l.acceptRun(validHexDigits) == 0 {
+			panic(fmt.Sprintf("invalid hexadecimal number %v", l.input[l.start:l.pos]))
+		}
+		return l.emit(ItemInteger)
+	}
+	return nil
+}
+
 func lexString(l *Lexer) (stateFn, *Item) {
-	// the current rune is known to be `"`. Throw it away in order to emit the raw string value
-	// e.g. example instead of "example".
+	// the current rune is known to be the opening quote. Throw it away in order to emit the raw string value
+	// e.g. example instead of "example". In lenient mode, the string may also be delimited by single quotes.
+	delimiter := rune(l.input[l.start])
 	l.ignore()
-	for r := l.next(); r != quote; r = l.next() {
+	for r := l.next(); r != delimiter; r = l.next() {
 		if r == backslash {
 			r = l.next()
 		}
@@ -247,9 +365,10 @@ func lexString(l *Lexer) (stateFn, *Item) {
 		}
 	}
 
-	// the current rune is the closing `"`. Throw this one away as well by decrementing the position. Reset the correct state after emitting the lexem.
+	// the current rune is the closing quote. Throw this one away as well by decrementing the position. Reset the correct state after emitting the lexem.
 	l.pos--
 	item := l.emit(ItemString)
+	item.Value = l.unescape(item.Value)
 	l.pos++
 	return lexWhitespace, item
 }
@@ -284,10 +403,25 @@ func lexRightSqrBrace(l *Lexer) (stateFn, *Item) {
 	return lexWhitespace, item
 }
 
-const ()
+func (l *Lexer) isWhitespace(r rune) bool {
+	if l.lenient {
+		// JSON5 accepts any Unicode space separator as well as the byte order mark.
+		return unicode.IsSpace(r) || r == '\uFEFF'
+	}
+	return isSpace(r)
+}
 
+// isSpace reports whether r is one of the four whitespace characters allowed by RFC 8259.
 func isSpace(r rune) bool {
-	return r == whitespace || r == tab
+	return r == whitespace || r == tab || r == newline || r == carriageReturn
+}
+
+func isIdentifierStart(r rune) bool {
+	return unicode.IsLetter(r) || r == dollar || r == underscore
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r)
 }
 
 func isNumber(r rune) bool {
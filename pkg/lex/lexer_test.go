@@ -29,7 +29,11 @@ var (
 
 // collect gathers the emitted items into a slice.
 func collect(t *lexTest) (items []*Item) {
-	l := Lex(t.input)
+	return collectWithOptions(t, Options{})
+}
+
+func collectWithOptions(t *lexTest, opts Options) (items []*Item) {
+	l := LexWithOptions(t.input, opts)
 	for {
 		item := l.NextItem()
 		items = append(items, item)
@@ -136,7 +140,7 @@ var lexTests = []lexTest{
 		iComma,
 		mkItem(ItemString, "string6"),
 		iColon,
-		mkItem(ItemString, "blabla \\\"xyz\\\""),
+		mkItem(ItemString, `blabla "xyz"`),
 		iRightBrace,
 		iEOF,
 	}},
@@ -187,6 +191,41 @@ var lexTests = []lexTest{
 		iRightSqrBrace,
 		iEOF,
 	}},
+	{"windows line endings", "{\r\n\t\"bool1\": true\r\n}\r\n", []*Item{
+		iLeftBrace,
+		mkItem(ItemString, "bool1"),
+		iColon,
+		mkItem(ItemBool, "true"),
+		iRightBrace,
+		iEOF,
+	}},
+	{"classic mac line endings", "[\rnull,\rnull\r]", []*Item{
+		iLeftSqrBrace,
+		mkItem(ItemNil, "null"),
+		iComma,
+		mkItem(ItemNil, "null"),
+		iRightSqrBrace,
+		iEOF,
+	}},
+	{"escaped strings", `["a\"b", "caf\u00e9", "\ud83d\ude00", "\ud800", "\\\/\b\f\n\r\t"]`, []*Item{
+		iLeftSqrBrace,
+		mkItem(ItemString, `a"b`),
+		iComma,
+		mkItem(ItemString, "café"),
+		iComma,
+		mkItem(ItemString, "😀"),
+		iComma,
+		mkItem(ItemString, "\uFFFD"),
+		iComma,
+		mkItem(ItemString, "\\/\b\f\n\r\t"),
+		iRightSqrBrace,
+		iEOF,
+	}},
+	{"leading byte order mark", "\uFEFF[]", []*Item{
+		iLeftSqrBrace,
+		iRightSqrBrace,
+		iEOF,
+	}},
 }
 
 func TestLex(t *testing.T) {
@@ -197,3 +236,112 @@ func TestLex(t *testing.T) {
 		}
 	}
 }
+
+var lenientLexTests = []lexTest{
+	{"line comments", "// leading\n{ // trailing\n}// eof", []*Item{
+		iLeftBrace,
+		iRightBrace,
+		iEOF,
+	}},
+	{"line comments with CR line endings", "// leading\r{ // trailing\r\"a\": 1\r}", []*Item{
+		iLeftBrace,
+		mkItem(ItemString, "a"),
+		iColon,
+		mkItem(ItemInteger, "1"),
+		iRightBrace,
+		iEOF,
+	}},
+	{"line comments with CRLF line endings", "// leading\r\n{ // trailing\r\n\"a\": 1\r\n}", []*Item{
+		iLeftBrace,
+		mkItem(ItemString, "a"),
+		iColon,
+		mkItem(ItemInteger, "1"),
+		iRightBrace,
+		iEOF,
+	}},
+	{"block comments", "/* leading */[ /* multi\nline */ true ]", []*Item{
+		iLeftSqrBrace,
+		mkItem(ItemBool, "true"),
+		iRightSqrBrace,
+		iEOF,
+	}},
+	{"unquoted keys and single-quoted strings", `{ key: 'value', $other_key1: 'it\'s' }`, []*Item{
+		iLeftBrace,
+		mkItem(ItemIdentifier, "key"),
+		iColon,
+		mkItem(ItemString, "value"),
+		iComma,
+		mkItem(ItemIdentifier, "$other_key1"),
+		iColon,
+		mkItem(ItemString, "it's"),
+		iRightBrace,
+		iEOF,
+	}},
+	{"JSON5 escapes", "[ '\\x41\\v\\0', 'line \\\r\ncontinued', '\\a' ]", []*Item{
+		iLeftSqrBrace,
+		mkItem(ItemString, "A\v\x00"),
+		iComma,
+		mkItem(ItemString, "line continued"),
+		iComma,
+		mkItem(ItemString, "a"),
+		iRightSqrBrace,
+		iEOF,
+	}},
+	{"keywords", `[ true, false, null ]`, []*Item{
+		iLeftSqrBrace,
+		mkItem(ItemBool, "true"),
+		iComma,
+		mkItem(ItemBool, "false"),
+		iComma,
+		mkItem(ItemNil, "null"),
+		iRightSqrBrace,
+		iEOF,
+	}},
+	{"JSON5 numbers", `[ 0x1F, -0XaB, NaN, Infinity, -Infinity, +1, .5, 5. ]`, []*Item{
+		iLeftSqrBrace,
+		mkItem(ItemInteger, "0x1F"),
+		iComma,
+		mkItem(ItemInteger, "-0XaB"),
+		iComma,
+		mkItem(ItemFloat, "NaN"),
+		iComma,
+		mkItem(ItemFloat, "Infinity"),
+		iComma,
+		mkItem(ItemFloat, "-Infinity"),
+		iComma,
+		mkItem(ItemInteger, "+1"),
+		iComma,
+		mkItem(ItemFloat, ".5"),
+		iComma,
+		mkItem(ItemFloat, "5."),
+		iRightSqrBrace,
+		iEOF,
+	}},
+}
+
+func TestLexLenient(t *testing.T) {
+	for _, test := range lenientLexTests {
+		items := collectWithOptions(&test, Options{Lenient: true})
+		if !equal(items, test.items, false) {
+			t.Errorf("%s: got\n\t%+v\nexpected\n\t%v", test.name, items, test.items)
+		}
+	}
+}
+
+func TestLexStrictRejectsLenientSyntax(t *testing.T) {
+	tests := []lexTest{
+		{name: "comment", input: "// comment\n{}"},
+		{name: "JSON5 escape", input: `["\x41"]`},
+		{name: "truncated unicode escape", input: `["\u00"]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic for %v in strict mode", test.input)
+				}
+			}()
+			collect(&test)
+		})
+	}
+}
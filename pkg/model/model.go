@@ -0,0 +1,275 @@
+// Package model describes the types inferred from the tree built by package parse, independent of the language
+// they are generated in. The backends of package generator translate it into Go, TypeScript and so on.
+package model
+
+import (
+	"strconv"
+
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+// MaxValues is the maximum number of distinct values recorded per Type.
+const MaxValues = 10
+
+// Kind is the kind of a Type.
+type Kind int
+
+// The kinds are ordered the way variants of a union are listed.
+const (
+	// KindAny is a value nothing is known about, e.g. the elements of an empty array.
+	KindAny Kind = iota
+	KindObject
+	KindArray
+	KindString
+	KindInteger
+	KindFloat
+	KindBool
+	KindNull
+	// KindUnion is a value that was observed with different kinds, see Type.Variants.
+	KindUnion
+)
+
+var kindNames = map[Kind]string{
+	KindAny:     "any",
+	KindObject:  "object",
+	KindArray:   "array",
+	KindString:  "string",
+	KindInteger: "integer",
+	KindFloat:   "float",
+	KindBool:    "bool",
+	KindNull:    "null",
+	KindUnion:   "union",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Type is the type of all values observed at the same place of the input, e.g. the values of a key in all
+// objects of an array.
+type Type struct {
+	Kind Kind
+	// Fields are the fields of an object in the order their keys first appeared.
+	Fields []*Field
+	// Elem is the type of the elements of an array. It's KindAny for arrays that were always empty.
+	Elem *Type
+	// Variants are the types of a union, one per kind, ordered by kind.
+	Variants []*Type
+	// Time reports whether all values of a string were RFC 3339 timestamps.
+	Time bool
+	// Count is the number of values the type was inferred from.
+	Count int
+	// Values are the distinct values of a primitive in the order of their first appearance, spelled like
+	// parse.PrimitiveNode.Literal. They are only known if the parser kept the values, and there are at most
+	// MaxValues of them.
+	Values []string
+	// MoreValues reports whether there were more distinct values than MaxValues.
+	MoreValues bool
+	// Zero reports whether the zero value of a primitive was observed, i.e. an empty string, 0 or false.
+	Zero bool
+}
+
+// Field is a key of an object.
+type Field struct {
+	Key  string
+	Type *Type
+	// Optional reports whether the key was missing in some of the objects the field was inferred from.
+	Optional bool
+}
+
+// Field returns the field with the given key, or nil if there is none.
+func (t *Type) Field(key string) *Field {
+	for _, field := range t.Fields {
+		if field.Key == key {
+			return field
+		}
+	}
+	return nil
+}
+
+// Enum returns the values of a string that repeatedly had one of a few distinct values, e.g. a status. A single
+// value isn't enough to tell an enum apart from a coincidence. It returns nil for anything else, including
+// strings whose values weren't kept.
+func (t *Type) Enum() []string {
+	if t.Kind != KindString || t.Time || len(t.Values) < 2 || t.MoreValues || t.Count <= len(t.Values) {
+		return nil
+	}
+	enum := make([]string, 0, len(t.Values))
+	for _, value := range t.Values {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil
+		}
+		enum = append(enum, unquoted)
+	}
+	return enum
+}
+
+// Nullable reports whether null was observed for t, i.e. whether t is null or a union with a null variant.
+func (t *Type) Nullable() bool {
+	return t.Kind == KindNull || t.Variant(KindNull) != nil
+}
+
+// Variant returns the variant of a union with the given kind, or nil if there is none.
+func (t *Type) Variant(kind Kind) *Type {
+	for _, variant := range t.Variants {
+		if variant.Kind == kind {
+			return variant
+		}
+	}
+	return nil
+}
+
+// WithoutNull returns t without its null variant. A union of null and a single other kind becomes that kind.
+func (t *Type) WithoutNull() *Type {
+	if t.Kind != KindUnion || t.Variant(KindNull) == nil {
+		return t
+	}
+	var variants []*Type
+	for _, variant := range t.Variants {
+		if variant.Kind != KindNull {
+			variants = append(variants, variant)
+		}
+	}
+	if len(variants) == 1 {
+		return variants[0]
+	}
+	return &Type{Kind: KindUnion, Variants: variants}
+}
+
+// Infer returns the type of samples, which are usually a single tree, but can be several samples of the same
+// data. All objects observed at the same place are merged into one object type, and so are all arrays.
+func Infer(samples ...parse.Node) *Type {
+	return inferFromNodes(samples)
+}
+
+// inferFromNodes returns the type describing all of nodes, which are values observed at the same place.
+func inferFromNodes(nodes []parse.Node) *Type {
+	var objects []*parse.ObjectNode
+	var arrays []*parse.ArrayNode
+	primitives := make(map[Kind][]parse.Node)
+	for _, node := range nodes {
+		switch node.Type() {
+		case parse.NodeTypeObject:
+			objects = append(objects, node.(*parse.ObjectNode))
+		case parse.NodeTypeArray:
+			arrays = append(arrays, node.(*parse.ArrayNode))
+		case parse.NodeTypeString:
+			primitives[KindString] = append(primitives[KindString], node)
+		case parse.NodeTypeInteger:
+			primitives[KindInteger] = append(primitives[KindInteger], node)
+		case parse.NodeTypeFloat:
+			primitives[KindFloat] = append(primitives[KindFloat], node)
+		case parse.NodeTypeBool:
+			primitives[KindBool] = append(primitives[KindBool], node)
+		case parse.NodeTypeNil:
+			primitives[KindNull] = append(primitives[KindNull], node)
+		}
+	}
+
+	var variants []*Type
+	for kind := KindObject; kind <= KindNull; kind++ {
+		switch {
+		case kind == KindObject && len(objects) > 0:
+			variants = append(variants, inferFromObjects(objects))
+		case kind == KindArray && len(arrays) > 0:
+			variants = append(variants, inferFromArrays(arrays))
+		case len(primitives[kind]) > 0:
+			variants = append(variants, inferFromPrimitives(kind, primitives[kind]))
+		}
+	}
+
+	switch len(variants) {
+	case 0:
+		return &Type{Kind: KindAny}
+	case 1:
+		return variants[0]
+	default:
+		return &Type{Kind: KindUnion, Variants: variants, Count: len(nodes)}
+	}
+}
+
+// inferFromPrimitives returns the type of nodes, which are all of the given kind. Their values are recorded if
+// the parser kept all of them.
+func inferFromPrimitives(kind Kind, nodes []parse.Node) *Type {
+	t := &Type{Kind: kind, Count: len(nodes), Time: kind == KindString}
+	isKept := make(map[string]bool)
+	allKept := true
+	for _, node := range nodes {
+		primitive, ok := node.(*parse.PrimitiveNode)
+		if !ok || !primitive.Time {
+			t.Time = false
+		}
+		if !ok || primitive.Literal == "" {
+			allKept = false
+			continue
+		}
+		if isZero(kind, primitive.Literal) {
+			t.Zero = true
+		}
+		switch {
+		case isKept[primitive.Literal]:
+		case len(t.Values) == MaxValues:
+			t.MoreValues = true
+		default:
+			t.Values = append(t.Values, primitive.Literal)
+			isKept[primitive.Literal] = true
+		}
+	}
+	if !allKept {
+		t.Values, t.MoreValues, t.Zero = nil, false, false
+	}
+	return t
+}
+
+func isZero(kind Kind, literal string) bool {
+	switch kind {
+	case KindString:
+		return literal == `""`
+	case KindInteger, KindFloat:
+		number, err := strconv.ParseFloat(literal, 64)
+		return err == nil && number == 0
+	case KindBool:
+		return literal == "false"
+	default:
+		return false
+	}
+}
+
+// inferFromObjects merges objects into a single object type. A field is optional unless its key is present in
+// all of them.
+func inferFromObjects(objects []*parse.ObjectNode) *Type {
+	var keys []string
+	values := make(map[string][]parse.Node)
+	presence := make(map[string]int)
+	for _, object := range objects {
+		for _, key := range object.OrderedKeys() {
+			if len(object.Children[key]) == 0 {
+				continue
+			}
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			values[key] = append(values[key], object.Children[key]...)
+			presence[key]++
+		}
+	}
+
+	t := &Type{Kind: KindObject, Count: len(objects)}
+	for _, key := range keys {
+		t.Fields = append(t.Fields, &Field{
+			Key:      key,
+			Type:     inferFromNodes(values[key]),
+			Optional: presence[key] < len(objects),
+		})
+	}
+	return t
+}
+
+func inferFromArrays(arrays []*parse.ArrayNode) *Type {
+	var children []parse.Node
+	for _, array := range arrays {
+		children = append(children, array.Children...)
+	}
+	return &Type{Kind: KindArray, Elem: inferFromNodes(children), Count: len(arrays)}
+}
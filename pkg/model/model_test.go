@@ -0,0 +1,172 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+// describe renders t compactly, e.g. `{a?:string|null}` or `[integer]`.
+func describe(t *Type) string {
+	switch t.Kind {
+	case KindObject:
+		var fields []string
+		for _, field := range t.Fields {
+			optional := ""
+			if field.Optional {
+				optional = "?"
+			}
+			fields = append(fields, field.Key+optional+":"+describe(field.Type))
+		}
+		return "{" + strings.Join(fields, ",") + "}"
+	case KindArray:
+		return "[" + describe(t.Elem) + "]"
+	case KindString:
+		if t.Time {
+			return "time"
+		}
+		return "string"
+	case KindUnion:
+		var variants []string
+		for _, variant := range t.Variants {
+			variants = append(variants, describe(variant))
+		}
+		return strings.Join(variants, "|")
+	default:
+		return t.Kind.String()
+	}
+}
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "primitives",
+			json: `{ "s": "a", "i": 1, "f": 1.5, "b": true, "n": null }`,
+			want: `{s:string,i:integer,f:float,b:bool,n:null}`,
+		},
+		{
+			name: "empty array",
+			json: `[]`,
+			want: `[any]`,
+		},
+		{
+			name: "optional keys of merged objects",
+			json: `[{ "id": 1, "name": "a" }, { "id": 2, "admin": true }]`,
+			want: `[{id:integer,name?:string,admin?:bool}]`,
+		},
+		{
+			name: "values of later objects are kept",
+			json: `[{ "a": 1, "b": [] }, { "a": "x", "b": [{ "c": null }] }]`,
+			want: `[{a:string|integer,b:[{c:null}]}]`,
+		},
+		{
+			name: "nested objects are merged",
+			json: `[{ "o": { "a": 1 } }, { "o": { "b": 2 } }, { "o": null }]`,
+			want: `[{o:{a?:integer,b?:integer}|null}]`,
+		},
+		{
+			name: "timestamps",
+			json: `{ "t": "2024-03-01T12:30:00Z", "u": ["2024-03-01T12:30:00Z", "tomorrow"] }`,
+			want: `{t:time,u:[string]}`,
+		},
+		{
+			name: "arrays of arrays",
+			json: `[[1, 2], [], ["a"]]`,
+			want: `[[string|integer]]`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, err := parse.ParseFromString(test.json)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describe(Infer(tree)); got != test.want {
+				t.Errorf("Infer(): got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestInferSeveralSamples(t *testing.T) {
+	first, _ := parse.ParseFromString(`{ "a": 1 }`)
+	second, _ := parse.ParseFromString(`{ "a": 2.5, "b": "x" }`)
+	if got, want := describe(Infer(first, second)), `{a:integer|float,b?:string}`; got != want {
+		t.Errorf("Infer(): got %v, want %v", got, want)
+	}
+}
+
+func TestWithoutNull(t *testing.T) {
+	tree, _ := parse.ParseFromString(`[1, null, "a"]`)
+	elem := Infer(tree).Elem
+	if !elem.Nullable() {
+		t.Errorf("Nullable(): got false for %v", describe(elem))
+	}
+	if got, want := describe(elem.WithoutNull()), "string|integer"; got != want {
+		t.Errorf("WithoutNull(): got %v, want %v", got, want)
+	}
+	nullableString := &Type{Kind: KindUnion, Variants: []*Type{{Kind: KindString}, {Kind: KindNull}}}
+	if got := nullableString.WithoutNull(); got.Kind != KindString {
+		t.Errorf("WithoutNull(): got %v, want string", describe(got))
+	}
+}
+
+func TestInferValues(t *testing.T) {
+	tree, err := parse.ParseFromStringWithOptions(`[
+		{ "status": "active", "id": "a1", "count": 0, "admin": true },
+		{ "status": "inactive", "id": "b2", "count": 3, "admin": true },
+		{ "status": "active", "id": "c3", "count": 4, "admin": true }
+	]`, parse.Options{KeepValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	elem := Infer(tree).Elem
+
+	status := elem.Field("status").Type
+	if got, want := strings.Join(status.Values, " "), `"active" "inactive"`; got != want {
+		t.Errorf("Values: got %v, want %v", got, want)
+	}
+	if got, want := strings.Join(status.Enum(), " "), "active inactive"; got != want {
+		t.Errorf("Enum(): got %v, want %v", got, want)
+	}
+	if status.Count != 3 {
+		t.Errorf("Count: got %v, want 3", status.Count)
+	}
+	if enum := elem.Field("id").Type.Enum(); enum != nil {
+		t.Errorf("Enum(): got %v for values that never repeat", enum)
+	}
+	if !elem.Field("count").Type.Zero {
+		t.Errorf("Zero: got false for a 0")
+	}
+	if elem.Field("admin").Type.Zero {
+		t.Errorf("Zero: got true without a false")
+	}
+
+	tree, _ = parse.ParseFromString(`[{ "status": "active" }, { "status": "active" }]`)
+	if values := Infer(tree).Elem.Field("status").Type.Values; values != nil {
+		t.Errorf("Values: got %v without parse.Options.KeepValues", values)
+	}
+}
+
+func TestInferTooManyValues(t *testing.T) {
+	json := "["
+	for i := 0; i <= MaxValues; i++ {
+		json += `"v` + strings.Repeat("x", i) + `", "v",`
+	}
+	tree, err := parse.ParseFromStringWithOptions(strings.TrimSuffix(json, ",")+"]", parse.Options{KeepValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	elem := Infer(tree).Elem
+	if len(elem.Values) != MaxValues || !elem.MoreValues {
+		t.Errorf("got %v values and MoreValues %v, want %v and true", len(elem.Values), elem.MoreValues, MaxValues)
+	}
+	if enum := elem.Enum(); enum != nil {
+		t.Errorf("Enum(): got %v despite too many values", enum)
+	}
+}
@@ -0,0 +1,185 @@
+// Package openapi extracts what json2struct can generate types from out of an OpenAPI 3 document: the schemas
+// in `components/schemas`, and the example payloads of request and response bodies.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/marhaupe/json2struct/pkg/jsonschema"
+)
+
+// SchemasPointer points to the named schemas of an OpenAPI document.
+const SchemasPointer = "#/components/schemas"
+
+// Document is the part of an OpenAPI 3 document that json2struct cares about.
+type Document struct {
+	// Schemas contains the schemas of `components/schemas` as its definitions.
+	Schemas *jsonschema.Document
+	// Examples are the JSON example payloads of all request and response bodies.
+	Examples []Example
+}
+
+// Example holds the example payloads of a request or response body.
+type Example struct {
+	// Name is a name for the type of the payloads, derived from the operation, e.g. `CreateUserRequest` or
+	// `GetUser200Response`.
+	Name string
+	// Values are the example payloads as JSON. Bodies can have several examples, which all describe the same type.
+	Values []json.RawMessage
+}
+
+// The HTTP methods in the order their operations are listed in.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type spec struct {
+	OpenAPI string `json:"openapi"`
+	// Path items also contain fields other than operations, e.g. `summary` or `parameters`.
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type operation struct {
+	OperationID string           `json:"operationId"`
+	RequestBody *body            `json:"requestBody"`
+	Responses   map[string]*body `json:"responses"`
+}
+
+type body struct {
+	Content map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Example  json.RawMessage            `json:"example"`
+	Examples map[string]json.RawMessage `json:"examples"`
+}
+
+// Parse parses an OpenAPI 3 document in JSON or YAML.
+func Parse(data []byte) (*Document, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		converted, err := yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing OpenAPI document: %v", err)
+		}
+		data = converted
+	}
+
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI document: %v", err)
+	}
+	if !strings.HasPrefix(s.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q. expected 3.x", s.OpenAPI)
+	}
+
+	schemas, err := jsonschema.ParseDefinitions(data, SchemasPointer)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{Schemas: schemas}
+
+	var paths []string
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range methods {
+			rawOp, ok := s.Paths[path][method]
+			if !ok {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(rawOp, &op); err != nil {
+				return nil, fmt.Errorf("error parsing operation %v %v: %v", strings.ToUpper(method), path, err)
+			}
+			opName := operationName(op.OperationID, method, path)
+			if values := exampleValues(op.RequestBody); len(values) > 0 {
+				doc.Examples = append(doc.Examples, Example{Name: opName + "Request", Values: values})
+			}
+
+			var statuses []string
+			for status := range op.Responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			for _, status := range statuses {
+				if values := exampleValues(op.Responses[status]); len(values) > 0 {
+					name := opName + strings.ToUpper(status[:1]) + status[1:] + "Response"
+					doc.Examples = append(doc.Examples, Example{Name: name, Values: values})
+				}
+			}
+		}
+	}
+	return doc, nil
+}
+
+// exampleValues returns the JSON example payloads of b.
+func exampleValues(b *body) []json.RawMessage {
+	if b == nil {
+		return nil
+	}
+
+	var mediaTypes []string
+	for mt := range b.Content {
+		if isJSON(mt) {
+			mediaTypes = append(mediaTypes, mt)
+		}
+	}
+	sort.Strings(mediaTypes)
+
+	var values []json.RawMessage
+	for _, mt := range mediaTypes {
+		content := b.Content[mt]
+		if len(content.Example) > 0 {
+			values = append(values, content.Example)
+		}
+
+		var exampleNames []string
+		for exampleName := range content.Examples {
+			exampleNames = append(exampleNames, exampleName)
+		}
+		sort.Strings(exampleNames)
+		for _, exampleName := range exampleNames {
+			// Example objects hold the payload in `value`. References to other examples aren't supported.
+			var example struct {
+				Value json.RawMessage `json:"value"`
+			}
+			if err := json.Unmarshal(content.Examples[exampleName], &example); err == nil && len(example.Value) > 0 {
+				values = append(values, example.Value)
+			}
+		}
+	}
+	return values
+}
+
+func isJSON(mediaType string) bool {
+	mediaType = strings.TrimSpace(strings.Split(mediaType, ";")[0])
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// operationName returns the operationId with an upper case first letter, or a name built from method and path
+// if the operation has no id, e.g. `GetUsersId` for `GET /users/{id}`.
+func operationName(operationID, method, path string) string {
+	if operationID == "" {
+		operationID = method + " " + path
+	}
+	var name strings.Builder
+	upperNext := true
+	for _, r := range operationID {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		name.WriteRune(r)
+	}
+	return name.String()
+}
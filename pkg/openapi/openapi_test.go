@@ -0,0 +1,114 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(`{
+		"openapi": "3.1.0",
+		"paths": {
+			"/users/{id}": {
+				"parameters": [{ "name": "id", "in": "path" }],
+				"put": {
+					"requestBody": {
+						"content": {
+							"application/json; charset=utf-8": {
+								"example": { "name": "Jane" },
+								"examples": { "b": { "value": { "name": "Joe" } }, "a": { "value": { "age": 3 } } }
+							},
+							"application/xml": { "example": "<user/>" }
+						}
+					}
+				},
+				"get": {
+					"operationId": "get-user",
+					"responses": {
+						"200": { "content": { "application/vnd.api+json": { "example": { "id": 1 } } } },
+						"404": { "description": "Not found" }
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": { "User": { "type": "object" }, "Error": { "type": "object" } }
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"#/components/schemas/Error", "#/components/schemas/User"}; !reflect.DeepEqual(doc.Schemas.Definitions(), want) {
+		t.Errorf("Parse(): got schemas %v, want %v", doc.Schemas.Definitions(), want)
+	}
+
+	var got []string
+	for _, example := range doc.Examples {
+		for _, value := range example.Values {
+			compacted := &bytes.Buffer{}
+			if err := json.Compact(compacted, value); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, example.Name+" "+compacted.String())
+		}
+	}
+	want := []string{
+		`GetUser200Response {"id":1}`,
+		`PutUsersIdRequest {"name":"Jane"}`,
+		`PutUsersIdRequest {"age":3}`,
+		`PutUsersIdRequest {"name":"Joe"}`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse(): got examples %q, want %q", got, want)
+	}
+}
+
+func TestParseRejectsOtherVersions(t *testing.T) {
+	if _, err := Parse([]byte(`{ "swagger": "2.0" }`)); err == nil {
+		t.Errorf("expected an error for a Swagger 2.0 document")
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "keeps the order of keys",
+			yaml: "b: 1\na: [true, null, 1.5, '2']\n",
+			want: `{"b":1,"a":[true,null,1.5,"2"]}`,
+		},
+		{
+			name: "spells keys as they are",
+			yaml: "200: {description: OK}\n",
+			want: `{"200":{"description":"OK"}}`,
+		},
+		{
+			name: "resolves aliases and merge keys",
+			yaml: "base: &base {a: 1, b: 2}\nderived: {<<: *base, b: 3}\n",
+			want: `{"base":{"a":1,"b":2},"derived":{"b":3,"a":1}}`,
+		},
+		{
+			name:    "rejects values JSON can't represent",
+			yaml:    "a: .inf\n",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(test.yaml))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("yamlToJSON(): got error %v, want error %v", err, test.wantErr)
+			}
+			if !test.wantErr && string(got) != test.want {
+				t.Errorf("yamlToJSON(): got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
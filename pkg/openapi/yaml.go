@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlToJSON converts a YAML document into JSON, keeping the order of the keys, which decoding into a map would
+// lose. Aliases are resolved and merge keys (`<<`) are applied. Keys are used as they are spelled, e.g. `200` for
+// the status code of a response.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("the document is empty")
+	}
+	buf := &bytes.Buffer{}
+	if err := writeJSON(buf, doc.Content[0]); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		pairs, err := mappingPairs(node)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i, pair := range pairs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(pair.key)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, pair.value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, child); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		value, err := scalarToJSON(node)
+		if err != nil {
+			return err
+		}
+		buf.Write(value)
+	}
+	return nil
+}
+
+type pair struct {
+	key   string
+	value *yaml.Node
+}
+
+// mappingPairs returns the key value pairs of mapping in their order. Keys of merged mappings come after the
+// keys of mapping itself, which take precedence.
+func mappingPairs(mapping *yaml.Node) ([]pair, error) {
+	var pairs, merged []pair
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := resolveAlias(mapping.Content[i]), mapping.Content[i+1]
+		if key.ShortTag() != "!!merge" {
+			pairs = append(pairs, pair{key: key.Value, value: value})
+			seen[key.Value] = true
+			continue
+		}
+
+		mappings := []*yaml.Node{resolveAlias(value)}
+		if mappings[0].Kind == yaml.SequenceNode {
+			mappings = mappings[0].Content
+		}
+		for _, m := range mappings {
+			if m = resolveAlias(m); m.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("a merge key needs a mapping as its value at line %v", m.Line)
+			}
+			mergedPairs, err := mappingPairs(m)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, mergedPairs...)
+		}
+	}
+	for _, p := range merged {
+		if !seen[p.key] {
+			pairs = append(pairs, p)
+			seen[p.key] = true
+		}
+	}
+	return pairs, nil
+}
+
+// scalarToJSON returns the JSON value of a scalar. Timestamps and binary data become strings.
+func scalarToJSON(node *yaml.Node) ([]byte, error) {
+	switch node.ShortTag() {
+	case "!!null", "!!bool", "!!int", "!!float":
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("the value %v at line %v can't be represented in JSON", node.Value, node.Line)
+		}
+		return data, nil
+	default:
+		return json.Marshal(node.Value)
+	}
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
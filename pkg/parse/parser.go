@@ -3,6 +3,10 @@ package parse
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"unicode"
 
 	"github.com/marhaupe/json2struct/pkg/lex"
 )
@@ -11,6 +15,61 @@ type Parser struct {
 	Lexer    *lex.Lexer
 	Item     *lex.Item
 	LastItem *lex.Item
+
+	opts Options
+}
+
+// Options configures optional behavior of the Parser.
+type Options struct {
+	// Lenient makes the Parser accept JSONC and JSON5 input: comments, trailing commas, unquoted keys,
+	// single-quoted strings, hexadecimal numbers and NaN/Infinity. The resulting tree is the same as for the
+	// equivalent plain JSON.
+	Lenient bool
+	// DuplicateKeys decides what happens when a key appears more than once within a single object.
+	DuplicateKeys DuplicateKeyPolicy
+	// Warn is called with a message for every recoverable problem in the input, e.g. a duplicate key.
+	// It may be nil.
+	Warn func(msg string)
+	// KeepValues makes the Parser record the value of every primitive in PrimitiveNode.Literal. It costs an
+	// allocation per value, which is why it's off by default.
+	KeepValues bool
+}
+
+// DuplicateKeyPolicy decides how the Parser handles a key that appears more than once within a single object.
+// Keys shared by different objects, e.g. the elements of an array, are not affected.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysMerge keeps every value of a duplicate key, as if they were observed in different samples.
+	DuplicateKeysMerge DuplicateKeyPolicy = iota
+	// DuplicateKeysLastWins keeps only the last value of a duplicate key, matching encoding/json.
+	DuplicateKeysLastWins
+	// DuplicateKeysError makes parsing fail.
+	DuplicateKeysError
+)
+
+var duplicateKeyPolicyNames = map[DuplicateKeyPolicy]string{
+	DuplicateKeysMerge:    "merge",
+	DuplicateKeysLastWins: "last",
+	DuplicateKeysError:    "error",
+}
+
+func (p DuplicateKeyPolicy) String() string {
+	if name, ok := duplicateKeyPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("DuplicateKeyPolicy(%d)", int(p))
+}
+
+// ParseDuplicateKeyPolicy returns the DuplicateKeyPolicy for its name as used by the CLI, i.e. "merge", "last"
+// or "error".
+func ParseDuplicateKeyPolicy(name string) (DuplicateKeyPolicy, error) {
+	for policy, policyName := range duplicateKeyPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid duplicate key policy %q. expected merge, last or error", name)
 }
 
 type Node interface {
@@ -40,17 +99,47 @@ type ArrayNode struct {
 
 type ObjectNode struct {
 	NodeType
-	// The JSON spec allows different types of values for the same key. Because of that, a simple `map[string]Node` is not enough.
+	// The JSON spec allows different types of values for the same key, e.g. when objects of an array are merged
+	// or duplicate keys are merged. Because of that, a simple `map[string]Node` is not enough.
 	Children map[string][]Node
+	// Keys lists the keys of Children in the order of their first appearance in the input.
+	Keys []string
+}
+
+// OrderedKeys returns the keys of Children in the order of their first appearance. Keys that are missing from
+// Keys, e.g. because the node wasn't built by the Parser, are appended in alphabetical order.
+func (o *ObjectNode) OrderedKeys() []string {
+	keys := make([]string, 0, len(o.Children))
+	isListed := make(map[string]bool, len(o.Children))
+	for _, key := range o.Keys {
+		if _, ok := o.Children[key]; ok && !isListed[key] {
+			keys = append(keys, key)
+			isListed[key] = true
+		}
+	}
+	var unlisted []string
+	for key := range o.Children {
+		if !isListed[key] {
+			unlisted = append(unlisted, key)
+		}
+	}
+	sort.Strings(unlisted)
+	return append(keys, unlisted...)
 }
 
 type PrimitiveNode struct {
 	NodeType
+	// Time reports whether the node is a string holding an RFC 3339 timestamp, e.g. "2006-01-02T15:04:05Z".
+	Time bool
+	// Literal is the value as it would be written in Go, e.g. `"active"`, `42` or `true`. It's only set if
+	// Options.KeepValues is.
+	Literal string
 }
 
 // Singleton primitive nodes to avoid allocations
 var (
 	stringNode  = &PrimitiveNode{NodeType: NodeTypeString}
+	timeNode    = &PrimitiveNode{NodeType: NodeTypeString, Time: true}
 	boolNode    = &PrimitiveNode{NodeType: NodeTypeBool}
 	nilNode     = &PrimitiveNode{NodeType: NodeTypeNil}
 	floatNode   = &PrimitiveNode{NodeType: NodeTypeFloat}
@@ -58,8 +147,13 @@ var (
 )
 
 func ParseFromString(j string) (Node, error) {
+	return ParseFromStringWithOptions(j, Options{})
+}
+
+func ParseFromStringWithOptions(j string, opts Options) (Node, error) {
 	parser := &Parser{
-		Lexer: lex.Lex(j),
+		Lexer: lex.LexWithOptions(j, lex.Options{Lenient: opts.Lenient}),
+		opts:  opts,
 	}
 	return parser.parse()
 }
@@ -96,6 +190,9 @@ func (p *Parser) parseObject() *ObjectNode {
 
 	var currentKey string
 	for p.Item = p.Lexer.NextItem(); p.Item.Typ != lex.ItemRightBrace; p.Item = p.Lexer.NextItem() {
+		if p.opts.Lenient && p.isUnquotedKey() {
+			p.Item.Typ = lex.ItemString
+		}
 
 		switch p.Item.Typ {
 		case lex.ItemString:
@@ -105,25 +202,31 @@ func (p *Parser) parseObject() *ObjectNode {
 			// It's a value if the previous lexem is a colon.
 			if p.LastItem.Typ == lex.ItemComma || p.LastItem.Typ == lex.ItemLeftBrace {
 				currentKey = p.Item.Value
+				p.opts.addKey(object, currentKey, p.Item.Pos)
 			} else {
-				object.Children[currentKey] = append(object.Children[currentKey], stringNode)
+				object.Children[currentKey] = append(object.Children[currentKey], p.opts.keepValue(makeStringNode(p.Item.Value), p.Item.Value))
 			}
 		case lex.ItemLeftBrace:
 			object.Children[currentKey] = append(object.Children[currentKey], p.parseObject())
 		case lex.ItemLeftSqrBrace:
 			object.Children[currentKey] = append(object.Children[currentKey], p.parseArray())
 		case lex.ItemBool:
-			object.Children[currentKey] = append(object.Children[currentKey], boolNode)
+			object.Children[currentKey] = append(object.Children[currentKey], p.opts.keepValue(boolNode, p.Item.Value))
 		case lex.ItemNil:
-			object.Children[currentKey] = append(object.Children[currentKey], nilNode)
+			object.Children[currentKey] = append(object.Children[currentKey], p.opts.keepValue(nilNode, p.Item.Value))
 		case lex.ItemFloat:
-			object.Children[currentKey] = append(object.Children[currentKey], floatNode)
+			object.Children[currentKey] = append(object.Children[currentKey], p.opts.keepValue(floatNode, p.Item.Value))
 		case lex.ItemInteger:
-			object.Children[currentKey] = append(object.Children[currentKey], integerNode)
+			object.Children[currentKey] = append(object.Children[currentKey], p.opts.keepValue(integerNode, p.Item.Value))
 		case lex.ItemColon:
 			break
 		case lex.ItemComma:
-			break
+			switch p.LastItem.Typ {
+			case lex.ItemLeftBrace, lex.ItemColon, lex.ItemComma:
+				panic(fmt.Sprintf("error parsing object. unexpected comma at pos %v", p.Item.Pos))
+			}
+		case lex.ItemIdentifier:
+			panic(fmt.Sprintf("error parsing object. the unquoted string %v is only allowed as a key", p.Item.Value))
 		case lex.ItemError:
 			panic(fmt.Sprintf("received error from lexer. pos: %v, msg: %v", p.Item.Pos, p.Item.Value))
 		default:
@@ -132,7 +235,7 @@ func (p *Parser) parseObject() *ObjectNode {
 		p.LastItem = p.Item
 	}
 
-	if p.LastItem.Typ == lex.ItemComma {
+	if p.LastItem.Typ == lex.ItemComma && !p.opts.Lenient {
 		panic("error parsing object. a closing curly brace mustn't follow a comma")
 	}
 
@@ -153,17 +256,21 @@ func (p *Parser) parseArray() *ArrayNode {
 		case lex.ItemLeftSqrBrace:
 			array.Children = append(array.Children, p.parseArray())
 		case lex.ItemNil:
-			array.Children = append(array.Children, nilNode)
+			array.Children = append(array.Children, p.opts.keepValue(nilNode, p.Item.Value))
 		case lex.ItemBool:
-			array.Children = append(array.Children, boolNode)
+			array.Children = append(array.Children, p.opts.keepValue(boolNode, p.Item.Value))
 		case lex.ItemString:
-			array.Children = append(array.Children, stringNode)
+			array.Children = append(array.Children, p.opts.keepValue(makeStringNode(p.Item.Value), p.Item.Value))
 		case lex.ItemInteger:
-			array.Children = append(array.Children, integerNode)
+			array.Children = append(array.Children, p.opts.keepValue(integerNode, p.Item.Value))
 		case lex.ItemFloat:
-			array.Children = append(array.Children, floatNode)
+			array.Children = append(array.Children, p.opts.keepValue(floatNode, p.Item.Value))
 		case lex.ItemComma:
-			break
+			if p.LastItem.Typ == lex.ItemLeftSqrBrace || p.LastItem.Typ == lex.ItemComma {
+				panic(fmt.Sprintf("error parsing array: unexpected comma at pos %v", p.Item.Pos))
+			}
+		case lex.ItemIdentifier:
+			panic(fmt.Sprintf("error parsing array: the unquoted string %v is only allowed as a key", p.Item.Value))
 		case lex.ItemError:
 			panic(fmt.Sprintf("received error from lexer. pos: %v, msg: %v", p.Item.Pos, p.Item.Value))
 		default:
@@ -172,9 +279,72 @@ func (p *Parser) parseArray() *ArrayNode {
 		p.LastItem = p.Item
 	}
 
-	if p.LastItem.Typ == lex.ItemComma {
+	if p.LastItem.Typ == lex.ItemComma && !p.opts.Lenient {
 		panic("error parsing array: a closing square brace mustn't follow a comma")
 	}
 
 	return array
 }
+
+// makeStringNode returns the node for the string value, which tells timestamps apart from other strings.
+func makeStringNode(value string) *PrimitiveNode {
+	// Checking the shape first keeps time.Parse away from the vast majority of strings.
+	if len(value) >= len("2006-01-02T15:04:05Z") && value[4] == '-' && value[7] == '-' && value[10] == 'T' {
+		if _, err := time.Parse(time.RFC3339, value); err == nil {
+			return timeNode
+		}
+	}
+	return stringNode
+}
+
+// keepValue returns node, or a copy of node with value as its literal if o.KeepValues is set. Strings are
+// quoted.
+func (o Options) keepValue(node *PrimitiveNode, value string) *PrimitiveNode {
+	if !o.KeepValues {
+		return node
+	}
+	kept := *node
+	kept.Literal = value
+	if node.NodeType == NodeTypeString {
+		kept.Literal = strconv.Quote(value)
+	}
+	return &kept
+}
+
+// addKey adds key without any values to object. If object already has the key, the duplicate key policy
+// decides what happens. pos is the position of the key in the input, which is part of the messages.
+func (o Options) addKey(object *ObjectNode, key string, pos any) {
+	if _, isDuplicate := object.Children[key]; !isDuplicate {
+		object.Children[key] = nil
+		object.Keys = append(object.Keys, key)
+		return
+	}
+
+	msg := fmt.Sprintf("duplicate key %q in object. pos: %v", key, pos)
+	switch o.DuplicateKeys {
+	case DuplicateKeysError:
+		panic(msg)
+	case DuplicateKeysLastWins:
+		object.Children[key] = nil
+	}
+	if o.Warn != nil {
+		o.Warn(msg)
+	}
+}
+
+// isUnquotedKey reports whether the current item is an identifier or a keyword like `null` or `true` that is
+// used as an unquoted key, which JSON5 allows.
+func (p *Parser) isUnquotedKey() bool {
+	isKeyPosition := p.LastItem.Typ == lex.ItemComma || p.LastItem.Typ == lex.ItemLeftBrace
+	if !isKeyPosition {
+		return false
+	}
+	switch p.Item.Typ {
+	case lex.ItemIdentifier:
+		return true
+	case lex.ItemBool, lex.ItemNil, lex.ItemFloat:
+		return unicode.IsLetter(rune(p.Item.Value[0]))
+	default:
+		return false
+	}
+}
@@ -5,9 +5,10 @@ import (
 	"testing"
 )
 
-func mkObjectNode(children map[string][]Node) *ObjectNode {
+func mkObjectNode(keys []string, children map[string][]Node) *ObjectNode {
 	return &ObjectNode{
 		Children: children,
+		Keys:     keys,
 		NodeType: NodeTypeObject,
 	}
 }
@@ -78,6 +79,7 @@ func TestParseArrayFromString(t *testing.T) {
 			want: mkArrayNode(
 				[]Node{
 					mkObjectNode(
+						[]string{"teststring"},
 						map[string][]Node{
 							"teststring": []Node{mkPrim(NodeTypeString)},
 						},
@@ -130,7 +132,7 @@ func TestParseObjectFromString(t *testing.T) {
 			args: args{
 				json: `{}`,
 			},
-			want: mkObjectNode(make(map[string][]Node)),
+			want: mkObjectNode(nil, make(map[string][]Node)),
 		},
 		{
 			name: "Object with primitives",
@@ -144,6 +146,7 @@ func TestParseObjectFromString(t *testing.T) {
 					}`,
 			},
 			want: mkObjectNode(
+				[]string{"teststring", "testbool", "testfloat", "testint", "testnil"},
 				map[string][]Node{
 					"teststring": []Node{mkPrim(NodeTypeString)},
 					"testbool":   []Node{mkPrim(NodeTypeBool)},
@@ -159,9 +162,11 @@ func TestParseObjectFromString(t *testing.T) {
 				json: `{ "testobject": { "teststring": "hi" }}`,
 			},
 			want: mkObjectNode(
+				[]string{"testobject"},
 				map[string][]Node{
 					"testobject": []Node{
 						mkObjectNode(
+							[]string{"teststring"},
 							map[string][]Node{
 								"teststring": []Node{mkPrim(NodeTypeString)},
 							},
@@ -176,6 +181,7 @@ func TestParseObjectFromString(t *testing.T) {
 				json: `{ "testarray": [ "hi", "ho" ]}`,
 			},
 			want: mkObjectNode(
+				[]string{"testarray"},
 				map[string][]Node{
 					"testarray": []Node{
 						mkArrayNode(
@@ -215,6 +221,18 @@ func TestParseInvalidJSON(t *testing.T) {
 			name: "object with comma before closing curly brace",
 			json: `{ "test": "hi", }`,
 		},
+		{
+			name: "array with leading comma",
+			json: "[ , true ]",
+		},
+		{
+			name: "array with consecutive commas",
+			json: "[ true, , false ]",
+		},
+		{
+			name: "object with comma instead of a value",
+			json: `{ "test": , "other": 1 }`,
+		},
 	}
 
 	for _, test := range tests {
@@ -223,3 +241,188 @@ func TestParseInvalidJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		name    string
+		lenient string
+		strict  string
+	}{
+		{
+			name: "comments",
+			lenient: `// config
+			{
+				"name": "test", // the name
+				/* the version */ "version": 1
+			}`,
+			strict: `{ "name": "test", "version": 1 }`,
+		},
+		{
+			name:    "comments with CR line endings",
+			lenient: "// c\r{\"a\": 1 // d\r}\r",
+			strict:  `{ "a": 1 }`,
+		},
+		{
+			name:    "comments with CRLF line endings",
+			lenient: "// c\r\n{\"a\": 1 // d\r\n}\r\n",
+			strict:  `{ "a": 1 }`,
+		},
+		{
+			name:    "trailing commas",
+			lenient: `{ "list": [ 1, 2, ], "obj": { "a": true, }, }`,
+			strict:  `{ "list": [ 1, 2 ], "obj": { "a": true } }`,
+		},
+		{
+			name:    "unquoted keys and single-quoted strings",
+			lenient: `{ name: 'test', null: 'keyword as key', nested: { inner: "double" } }`,
+			strict:  `{ "name": "test", "null": "keyword as key", "nested": { "inner": "double" } }`,
+		},
+		{
+			name:    "JSON5 numbers",
+			lenient: `{ hex: 0xFF, nan: NaN, inf: -Infinity, half: .5 }`,
+			strict:  `{ "hex": 255, "nan": 1.0, "inf": 1.0, "half": 0.5 }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFromStringWithOptions(tt.lenient, Options{Lenient: true})
+			if err != nil {
+				t.Fatalf("ParseFromStringWithOptions(): got error %v", err)
+			}
+			want, err := ParseFromString(tt.strict)
+			if err != nil {
+				t.Fatalf("ParseFromString(): got error %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseFromStringWithOptions(): \ngot:\n %#v \nwant:\n %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseLenientInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "unquoted string as value", json: `{ a: foo }`},
+		{name: "unquoted string in array", json: `[ foo ]`},
+		{name: "comma without element", json: `[,]`},
+		{name: "comma without member", json: `{,}`},
+		{name: "consecutive commas", json: `{ a: 1,, b: 2 }`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseFromStringWithOptions(test.json, Options{Lenient: true}); err == nil {
+				t.Errorf("ParseFromStringWithOptions(): expected an error for %v", test.json)
+			}
+		})
+	}
+}
+
+func TestParseDuplicateKeys(t *testing.T) {
+	const json = `{ "a": "first", "b": true, "a": 1 }`
+	tests := []struct {
+		name         string
+		policy       DuplicateKeyPolicy
+		want         Node
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name:   "merge",
+			policy: DuplicateKeysMerge,
+			want: mkObjectNode(
+				[]string{"a", "b"},
+				map[string][]Node{
+					"a": []Node{mkPrim(NodeTypeString), mkPrim(NodeTypeInteger)},
+					"b": []Node{mkPrim(NodeTypeBool)},
+				},
+			),
+			wantWarnings: 1,
+		},
+		{
+			name:   "last wins",
+			policy: DuplicateKeysLastWins,
+			want: mkObjectNode(
+				[]string{"a", "b"},
+				map[string][]Node{
+					"a": []Node{mkPrim(NodeTypeInteger)},
+					"b": []Node{mkPrim(NodeTypeBool)},
+				},
+			),
+			wantWarnings: 1,
+		},
+		{
+			name:    "error",
+			policy:  DuplicateKeysError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []string
+			got, err := ParseFromStringWithOptions(json, Options{
+				DuplicateKeys: tt.policy,
+				Warn:          func(msg string) { warnings = append(warnings, msg) },
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFromStringWithOptions(): got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFromStringWithOptions(): \ngot:\n %#v \nwant:\n %#v", got, tt.want)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("ParseFromStringWithOptions(): got warnings %v, want %v", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestParseSameKeyInDifferentObjects(t *testing.T) {
+	_, err := ParseFromStringWithOptions(`[{ "a": 1 }, { "a": 2 }]`, Options{DuplicateKeys: DuplicateKeysError})
+	if err != nil {
+		t.Errorf("ParseFromStringWithOptions(): keys of different objects mustn't be duplicates, got error %v", err)
+	}
+}
+
+func TestParseTimestamps(t *testing.T) {
+	tree, err := ParseFromString(`[
+		"2024-03-01T12:30:00Z",
+		"2024-03-01T12:30:00.123+02:00",
+		"2024-03-01",
+		"2024-03-01 12:30:00Z",
+		"2024-13-01T12:30:00Z",
+		"not a timestamp at all"
+	]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bool{true, true, false, false, false, false}
+	for i, child := range tree.(*ArrayNode).Children {
+		if got := child.(*PrimitiveNode).Time; got != want[i] {
+			t.Errorf("element %v: got Time %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseKeepValues(t *testing.T) {
+	tree, err := ParseFromStringWithOptions(`["a\"b", 1.5e3, -2, true, null, ""]`, Options{KeepValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`"a\"b"`, `1.5e3`, `-2`, `true`, `null`, `""`}
+	for i, child := range tree.(*ArrayNode).Children {
+		if got := child.(*PrimitiveNode).Literal; got != want[i] {
+			t.Errorf("element %v: got Literal %v, want %v", i, got, want[i])
+		}
+	}
+
+	tree, _ = ParseFromString(`{ "a": "b" }`)
+	if literal := tree.(*ObjectNode).Children["a"][0].(*PrimitiveNode).Literal; literal != "" {
+		t.Errorf("got Literal %v without KeepValues", literal)
+	}
+}
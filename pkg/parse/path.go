@@ -0,0 +1,187 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathSegment is a step of a Path: a key of an object, an index of an array, or a wildcard selecting all
+// elements of an array or values of an object.
+type PathSegment struct {
+	Key string
+	// Index is the index of an array element. It's only used if IsIndex is set.
+	Index    int
+	IsIndex  bool
+	Wildcard bool
+}
+
+func (s PathSegment) String() string {
+	switch {
+	case s.Wildcard:
+		return "[*]"
+	case s.IsIndex:
+		return fmt.Sprintf("[%d]", s.Index)
+	default:
+		return s.Key
+	}
+}
+
+// Path selects a subtree of a Node, see ParsePath.
+type Path []PathSegment
+
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, segment := range p {
+		if !segment.Wildcard && !segment.IsIndex {
+			b.WriteString(".")
+		}
+		b.WriteString(segment.String())
+	}
+	return b.String()
+}
+
+// ParsePath parses a path as either a JSON Pointer or in the dot notation of JSONPath:
+//
+//   - A JSON Pointer starts with a slash, e.g. `/data/items/0`. Its tokens are keys, or indices if the value is an
+//     array. As an extension, `*` selects all elements of an array.
+//   - Otherwise, the path consists of keys separated by dots and brackets holding an index, `*` or a quoted key,
+//     e.g. `data.items[*]`, `$.data.items[0]` or `data['first name']`. A leading `$` is optional.
+//
+// The empty path selects the whole tree.
+func ParsePath(path string) (Path, error) {
+	if path == "" || path == "$" {
+		return nil, nil
+	}
+	if strings.HasPrefix(path, "/") {
+		return parsePointer(path)
+	}
+	return parseDotPath(path)
+}
+
+func parsePointer(pointer string) (Path, error) {
+	var p Path
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		segment := PathSegment{Key: token}
+		if token == "*" {
+			segment = PathSegment{Wildcard: true}
+		} else if index, err := strconv.Atoi(token); err == nil && index >= 0 && strconv.Itoa(index) == token {
+			// Whether the token is a key or an index depends on the value, see Select.
+			segment.Index = index
+		}
+		p = append(p, segment)
+	}
+	return p, nil
+}
+
+func parseDotPath(path string) (Path, error) {
+	invalid := func(reason string) (Path, error) {
+		return nil, fmt.Errorf("invalid path %q: %v", path, reason)
+	}
+	rest := strings.TrimPrefix(path, "$")
+	if rest != path && rest != "" && rest[0] != '.' && rest[0] != '[' {
+		return invalid("expected . or [ after $")
+	}
+	var p Path
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return invalid("empty key")
+			}
+			if rest[:end] == "*" {
+				p = append(p, PathSegment{Wildcard: true})
+			} else {
+				p = append(p, PathSegment{Key: rest[:end]})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return invalid("missing ]")
+			}
+			content := rest[1:end]
+			// Quoted keys may contain ], so the end is searched after the closing quote.
+			if len(content) > 0 && (content[0] == '\'' || content[0] == '"') {
+				closing := strings.IndexByte(rest[2:], rest[1])
+				if closing < 0 || len(rest) < closing+4 || rest[closing+3] != ']' {
+					return invalid("unterminated quoted key")
+				}
+				p = append(p, PathSegment{Key: rest[2 : closing+2]})
+				rest = rest[closing+4:]
+				continue
+			}
+			switch index, err := strconv.Atoi(content); {
+			case content == "*" || content == "":
+				p = append(p, PathSegment{Wildcard: true})
+			case err == nil && index >= 0:
+				p = append(p, PathSegment{Index: index, IsIndex: true})
+			default:
+				return invalid(fmt.Sprintf("expected an index, * or a quoted key in brackets, found %q", content))
+			}
+			rest = rest[end+1:]
+		default:
+			if len(p) > 0 || rest != strings.TrimPrefix(path, "$") {
+				return invalid(fmt.Sprintf("unexpected %q", rest[0]))
+			}
+			// The first key doesn't need a leading dot.
+			rest = "." + rest
+		}
+	}
+	return p, nil
+}
+
+// Select returns the nodes at p in tree. There may be several, e.g. for a wildcard, or for a key whose values
+// were merged since it appeared more than once in an object.
+func (p Path) Select(tree Node) ([]Node, error) {
+	nodes := []Node{tree}
+	for i, segment := range p {
+		var selected []Node
+		for _, node := range nodes {
+			switch node := node.(type) {
+			case *ObjectNode:
+				if segment.Wildcard {
+					for _, key := range node.OrderedKeys() {
+						selected = append(selected, node.Children[key]...)
+					}
+					continue
+				}
+				if segment.IsIndex {
+					return nil, fmt.Errorf("%v: expected an array, found an object", p[:i+1])
+				}
+				children, ok := node.Children[segment.Key]
+				if !ok {
+					return nil, fmt.Errorf("%v: no such key", p[:i+1])
+				}
+				selected = append(selected, children...)
+			case *ArrayNode:
+				if segment.Wildcard {
+					selected = append(selected, node.Children...)
+					continue
+				}
+				// Keys of JSON Pointers that look like indices select array elements.
+				if !segment.IsIndex && (segment.Key == "" || strconv.Itoa(segment.Index) != segment.Key) {
+					return nil, fmt.Errorf("%v: expected an object, found an array", p[:i+1])
+				}
+				if segment.Index >= len(node.Children) {
+					return nil, fmt.Errorf("%v: index out of range, the array has %d elements", p[:i+1], len(node.Children))
+				}
+				selected = append(selected, node.Children[segment.Index])
+			default:
+				return nil, fmt.Errorf("%v: expected an object or an array, found a primitive", p[:i+1])
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("%v: nothing selected, e.g. because of an empty array", p[:i+1])
+		}
+		nodes = selected
+	}
+	return nodes, nil
+}
@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Path
+		wantErr bool
+	}{
+		{path: "", want: nil},
+		{path: "$", want: nil},
+		{path: "data.items", want: Path{{Key: "data"}, {Key: "items"}}},
+		{path: "$.data.items[*]", want: Path{{Key: "data"}, {Key: "items"}, {Wildcard: true}}},
+		{path: "items[2].tags[]", want: Path{{Key: "items"}, {Index: 2, IsIndex: true}, {Key: "tags"}, {Wildcard: true}}},
+		{path: "$['first name'][\"a.b]\"]", want: Path{{Key: "first name"}, {Key: "a.b]"}}},
+		{path: "data.*", want: Path{{Key: "data"}, {Wildcard: true}}},
+		{path: "/data/items/0", want: Path{{Key: "data"}, {Key: "items"}, {Key: "0", Index: 0}}},
+		{path: "/a~1b/c~0d/*", want: Path{{Key: "a/b"}, {Key: "c~d"}, {Wildcard: true}}},
+		{path: "data..items", wantErr: true},
+		{path: "data[x]", wantErr: true},
+		{path: "data[-1]", wantErr: true},
+		{path: "data[0", wantErr: true},
+		{path: "data['x]", wantErr: true},
+		{path: "$data", wantErr: true},
+		{path: "items[0]x", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParsePath(test.path)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParsePath(%q): got error %v, want error %v", test.path, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParsePath(%q): got %#v, want %#v", test.path, got, test.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tree, err := ParseFromString(`{
+		"data": {"items": [{"id": 1}, {"id": 2, "name": "b"}], "count": 2},
+		"meta": {"0": "zero"},
+		"dup": {"a": 1}, "dup": {"a": "x"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    int
+		wantErr string
+	}{
+		{path: "", want: 1},
+		{path: "data.items", want: 1},
+		{path: "data.items[*]", want: 2},
+		{path: "data.items[1].name", want: 1},
+		{path: "/data/items/1", want: 1},
+		{path: "/meta/0", want: 1},
+		{path: "data.*", want: 2},
+		{path: "dup", want: 2},
+		{path: "data.missing", wantErr: "$.data.missing: no such key"},
+		{path: "data.items[2]", wantErr: "$.data.items[2]: index out of range, the array has 2 elements"},
+		{path: "data.items.id", wantErr: "$.data.items.id: expected an object, found an array"},
+		{path: "data[0]", wantErr: "$.data[0]: expected an array, found an object"},
+		{path: "data.count.x", wantErr: "$.data.count.x: expected an object or an array, found a primitive"},
+	}
+	for _, test := range tests {
+		p, err := ParsePath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.Select(tree)
+		if test.wantErr != "" {
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("Select(%q): got error %v, want %v", test.path, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Select(%q): %v", test.path, err)
+			continue
+		}
+		if len(got) != test.want {
+			t.Errorf("Select(%q): got %d nodes, want %d", test.path, len(got), test.want)
+		}
+	}
+
+	empty, _ := ParseFromString(`{"items": []}`)
+	p, _ := ParsePath("items[*]")
+	if _, err := p.Select(empty); err == nil {
+		t.Errorf("Select(): expected an error for an empty selection")
+	}
+}
@@ -0,0 +1,125 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ParseTOML converts a TOML document into the same tree ParseFromStringWithOptions builds for JSON. Offset
+// date-times become timestamps, while local dates and times become plain strings. TOML doesn't allow
+// duplicate keys, so opts.DuplicateKeys has no effect, and neither has opts.Lenient.
+func ParseTOML(s string, opts Options) (node Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			node = nil
+			err = errors.New(fmt.Sprint(r))
+		}
+	}()
+
+	var doc map[string]any
+	meta, err := toml.Decode(s, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing TOML: %v", err)
+	}
+
+	// Maps don't remember the order of their keys, but the metadata lists all keys in the order they appear.
+	order := make(map[string]int)
+	for i, key := range meta.Keys() {
+		path := key.String()
+		if _, ok := order[path]; !ok {
+			order[path] = i
+		}
+	}
+	c := &tomlConverter{order: order, opts: opts}
+	return c.convert(doc, ""), nil
+}
+
+type tomlConverter struct {
+	order map[string]int
+	opts  Options
+}
+
+// convert converts value, which is found at path, e.g. `servers.alpha`. The elements of arrays share the path
+// of the array.
+func (c *tomlConverter) convert(value any, path string) Node {
+	switch v := value.(type) {
+	case map[string]any:
+		object := &ObjectNode{
+			NodeType: NodeTypeObject,
+			Children: make(map[string][]Node, len(v)),
+		}
+		for key := range v {
+			object.Keys = append(object.Keys, key)
+		}
+		c.sortKeys(object.Keys, path)
+		for _, key := range object.Keys {
+			object.Children[key] = []Node{c.convert(v[key], c.childPath(path, key))}
+		}
+		return object
+	case []map[string]any:
+		array := &ArrayNode{NodeType: NodeTypeArray, Children: make([]Node, 0, len(v))}
+		for _, child := range v {
+			array.Children = append(array.Children, c.convert(child, path))
+		}
+		return array
+	case []any:
+		array := &ArrayNode{NodeType: NodeTypeArray, Children: make([]Node, 0, len(v))}
+		for _, child := range v {
+			array.Children = append(array.Children, c.convert(child, path))
+		}
+		return array
+	case string:
+		return c.opts.keepValue(makeStringNode(v), v)
+	case bool:
+		return c.opts.keepValue(boolNode, strconv.FormatBool(v))
+	case int64:
+		return c.opts.keepValue(integerNode, strconv.FormatInt(v, 10))
+	case float64:
+		return c.opts.keepValue(floatNode, strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Time:
+		// Local date-times, dates and times are decoded into time.Time as well, but they lack an offset. The
+		// decoder marks them with special locations.
+		switch v.Location().String() {
+		case "datetime-local":
+			return c.opts.keepValue(stringNode, v.Format("2006-01-02T15:04:05.999999999"))
+		case "date-local":
+			return c.opts.keepValue(stringNode, v.Format("2006-01-02"))
+		case "time-local":
+			return c.opts.keepValue(stringNode, v.Format("15:04:05.999999999"))
+		default:
+			return c.opts.keepValue(timeNode, v.Format(time.RFC3339Nano))
+		}
+	default:
+		panic(fmt.Sprintf("error parsing TOML: unexpected value %v of type %T at %v", v, v, path))
+	}
+}
+
+// sortKeys sorts the keys of the table at path in the order they appear in the document.
+func (c *tomlConverter) sortKeys(keys []string, path string) {
+	position := func(key string) int {
+		if i, ok := c.order[c.childPath(path, key)]; ok {
+			return i
+		}
+		return len(c.order)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if position(keys[i]) != position(keys[j]) {
+			return position(keys[i]) < position(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+func (c *tomlConverter) childPath(path, key string) string {
+	child := toml.Key{key}.String()
+	if path == "" {
+		return child
+	}
+	return strings.Join([]string{path, child}, ".")
+}
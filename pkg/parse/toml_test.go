@@ -0,0 +1,58 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tree, err := ParseTOML(`
+title = "example"
+ratio = 0.5
+
+[owner]
+name = "Tom"
+born = 1979-05-27T07:32:00-08:00
+birthday = 1979-05-27
+
+[[servers]]
+name = "alpha"
+port = 8080
+
+[[servers]]
+name = "beta"
+enabled = true
+`, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := mkObjectNode([]string{"title", "ratio", "owner", "servers"}, map[string][]Node{
+		"title": {stringNode},
+		"ratio": {floatNode},
+		"owner": {mkObjectNode([]string{"name", "born", "birthday"}, map[string][]Node{
+			"name":     {stringNode},
+			"born":     {timeNode},
+			"birthday": {stringNode},
+		})},
+		"servers": {mkArrayNode([]Node{
+			mkObjectNode([]string{"name", "port"}, map[string][]Node{
+				"name": {stringNode},
+				"port": {integerNode},
+			}),
+			mkObjectNode([]string{"name", "enabled"}, map[string][]Node{
+				"name":    {stringNode},
+				"enabled": {boolNode},
+			}),
+		})},
+	})
+	if !reflect.DeepEqual(tree, want) {
+		t.Errorf("ParseTOML(): got %#v, want %#v", tree, want)
+	}
+}
+
+func TestParseTOMLInvalid(t *testing.T) {
+	if _, err := ParseTOML("a = 1\na = 2\n", Options{}); err == nil {
+		t.Errorf("expected an error for a duplicate key")
+	}
+}
@@ -0,0 +1,145 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML converts a YAML document into the same tree ParseFromStringWithOptions builds for JSON. Aliases are
+// resolved and merge keys (`<<`) are applied. Of a stream with several documents, only the first one is used.
+// opts.Lenient has no effect.
+func ParseYAML(s string, opts Options) (node Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			node = nil
+			err = errors.New(fmt.Sprint(r))
+		}
+	}()
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, fmt.Errorf("error parsing YAML: %v", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("error parsing YAML: the document is empty")
+	}
+
+	c := &yamlConverter{opts: opts}
+	switch root := resolveAlias(doc.Content[0]); root.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		return c.convert(root), nil
+	default:
+		return nil, fmt.Errorf("error determining root YAML type. expected a mapping or a sequence at line %v", root.Line)
+	}
+}
+
+type yamlConverter struct {
+	opts Options
+}
+
+func (c *yamlConverter) convert(node *yaml.Node) Node {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		object := &ObjectNode{
+			NodeType: NodeTypeObject,
+			Children: make(map[string][]Node, len(node.Content)/2),
+		}
+		c.addPairs(object, node)
+		return object
+	case yaml.SequenceNode:
+		array := &ArrayNode{
+			NodeType: NodeTypeArray,
+			Children: make([]Node, 0, len(node.Content)),
+		}
+		for _, child := range node.Content {
+			array.Children = append(array.Children, c.convert(child))
+		}
+		return array
+	default:
+		return c.opts.keepValue(convertYAMLScalar(node), yamlLiteral(node))
+	}
+}
+
+// addPairs adds the key value pairs of mapping to object.
+func (c *yamlConverter) addPairs(object *ObjectNode, mapping *yaml.Node) {
+	merged := make(map[string]bool)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := resolveAlias(mapping.Content[i]), mapping.Content[i+1]
+		switch {
+		case key.ShortTag() == "!!merge":
+			c.merge(object, resolveAlias(value), merged)
+			continue
+		case merged[key.Value]:
+			// Keys of the mapping itself override merged keys, no matter where the merge key is.
+			object.Children[key.Value] = nil
+			delete(merged, key.Value)
+		default:
+			c.opts.addKey(object, key.Value, fmt.Sprintf("line %v", key.Line))
+		}
+		object.Children[key.Value] = append(object.Children[key.Value], c.convert(value))
+	}
+}
+
+// merge applies a merge key, whose value is a mapping or a sequence of mappings. Keys that object already has
+// take precedence, which is why they aren't duplicates. The keys added to object are recorded in merged.
+func (c *yamlConverter) merge(object *ObjectNode, value *yaml.Node, merged map[string]bool) {
+	mappings := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		mappings = value.Content
+	}
+	for _, mapping := range mappings {
+		mapping = resolveAlias(mapping)
+		if mapping.Kind != yaml.MappingNode {
+			panic(fmt.Sprintf("error parsing YAML: a merge key needs a mapping as its value at line %v", mapping.Line))
+		}
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			key := resolveAlias(mapping.Content[i]).Value
+			if _, ok := object.Children[key]; ok {
+				continue
+			}
+			object.Children[key] = []Node{c.convert(mapping.Content[i+1])}
+			object.Keys = append(object.Keys, key)
+			merged[key] = true
+		}
+	}
+}
+
+func convertYAMLScalar(node *yaml.Node) *PrimitiveNode {
+	switch node.ShortTag() {
+	case "!!null":
+		return nilNode
+	case "!!bool":
+		return boolNode
+	case "!!int":
+		return integerNode
+	case "!!float":
+		return floatNode
+	default:
+		// Strings, timestamps and binary data are all strings in JSON.
+		return makeStringNode(node.Value)
+	}
+}
+
+// yamlLiteral returns the value of a scalar the way JSON would spell it, e.g. `true` for `True` and `null` for
+// `~`.
+func yamlLiteral(node *yaml.Node) string {
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return strings.ToLower(node.Value)
+	default:
+		return node.Value
+	}
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,81 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tree, err := ParseYAML(`
+defaults: &defaults
+  replicas: 1
+  image: nginx
+service:
+  <<: *defaults
+  replicas: 3
+  ports: [80, 443]
+  ratio: .5
+  enabled: yes
+  debug: true
+  owner: ~
+  created: 2024-03-01T12:30:00Z
+  "quoted key": value
+`, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := tree.(*ObjectNode)
+	if want := []string{"defaults", "service"}; !reflect.DeepEqual(root.Keys, want) {
+		t.Errorf("ParseYAML(): got keys %v, want %v", root.Keys, want)
+	}
+	service := root.Children["service"][0].(*ObjectNode)
+	wantKeys := []string{"replicas", "image", "ports", "ratio", "enabled", "debug", "owner", "created", "quoted key"}
+	if !reflect.DeepEqual(service.Keys, wantKeys) {
+		t.Errorf("ParseYAML(): got keys %v, want %v", service.Keys, wantKeys)
+	}
+	wantChildren := map[string][]Node{
+		"replicas":   {integerNode},
+		"image":      {stringNode},
+		"ports":      {mkArrayNode([]Node{integerNode, integerNode})},
+		"ratio":      {floatNode},
+		"enabled":    {stringNode},
+		"debug":      {boolNode},
+		"owner":      {nilNode},
+		"created":    {timeNode},
+		"quoted key": {stringNode},
+	}
+	if !reflect.DeepEqual(service.Children, wantChildren) {
+		t.Errorf("ParseYAML(): got children %#v, want %#v", service.Children, wantChildren)
+	}
+}
+
+func TestParseYAMLDuplicateKeys(t *testing.T) {
+	input := "a: 1\na: x\n"
+	if _, err := ParseYAML(input, Options{DuplicateKeys: DuplicateKeysError}); err == nil {
+		t.Errorf("expected an error for a duplicate key")
+	}
+
+	var warnings []string
+	tree, err := ParseYAML(input, Options{
+		DuplicateKeys: DuplicateKeysLastWins,
+		Warn:          func(msg string) { warnings = append(warnings, msg) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Node{stringNode}; !reflect.DeepEqual(tree.(*ObjectNode).Children["a"], want) {
+		t.Errorf("ParseYAML(): got %v for a, want only the last value", tree.(*ObjectNode).Children["a"])
+	}
+	if want := []string{`duplicate key "a" in object. pos: line 2`}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("ParseYAML(): got warnings %q, want %q", warnings, want)
+	}
+}
+
+func TestParseYAMLInvalidRoot(t *testing.T) {
+	for _, input := range []string{"", "just a string", "a: [1"} {
+		if _, err := ParseYAML(input, Options{}); err == nil {
+			t.Errorf("ParseYAML(%q): expected an error", input)
+		}
+	}
+}
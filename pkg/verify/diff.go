@@ -0,0 +1,189 @@
+package verify
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+)
+
+// DifferenceKind is the kind of a Difference.
+type DifferenceKind int
+
+const (
+	// DifferenceMissingField is a key of the samples without a field.
+	DifferenceMissingField DifferenceKind = iota
+	// DifferenceExtraField is a field whose key isn't in any sample.
+	DifferenceExtraField
+	// DifferenceTypeMismatch is a value that doesn't fit the type of its field.
+	DifferenceTypeMismatch
+	// DifferenceTagMismatch is a key that only matches the json tag of its field case-insensitively.
+	DifferenceTagMismatch
+)
+
+var differenceKindNames = map[DifferenceKind]string{
+	DifferenceMissingField: "missing field",
+	DifferenceExtraField:   "extra field",
+	DifferenceTypeMismatch: "type mismatch",
+	DifferenceTagMismatch:  "tag mismatch",
+}
+
+func (k DifferenceKind) String() string {
+	if name, ok := differenceKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("DifferenceKind(%d)", int(k))
+}
+
+// Difference is a mismatch between a Go type and the shape inferred from samples.
+type Difference struct {
+	Kind DifferenceKind
+	// Path locates the value, e.g. `users[].age`. Elements of arrays share the path of the array.
+	Path string
+	// Msg describes the difference.
+	Msg string
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%v: %v: %v", d.Kind, path, d.Msg)
+}
+
+// Diff compares typ to shape, which is usually inferred from samples, and returns their differences. Integers
+// are only checked for overflows if their values were kept, and only up to model.MaxValues of them.
+func Diff(typ types.Type, shape *model.Type) []Difference {
+	d := &differ{}
+	d.diff(typ, shape, "")
+	return d.differences
+}
+
+// differ holds the state of a single run of Diff.
+type differ struct {
+	differences []Difference
+}
+
+func (d *differ) report(kind DifferenceKind, path, format string, args ...any) {
+	d.differences = append(d.differences, Difference{Kind: kind, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// diff compares typ to shape, which describes the values found at path.
+func (d *differ) diff(typ types.Type, shape *model.Type, path string) {
+	if implementsUnmarshaler(typ) {
+		return
+	}
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		d.diff(pointer.Elem(), shape, path)
+		return
+	}
+	if iface, ok := typ.Underlying().(*types.Interface); ok && iface.Empty() {
+		return
+	}
+
+	// encoding/json accepts null for any type.
+	switch shape = shape.WithoutNull(); shape.Kind {
+	case model.KindAny, model.KindNull:
+	case model.KindUnion:
+		for _, variant := range shape.Variants {
+			d.diff(typ, variant, path)
+		}
+	case model.KindObject:
+		d.diffObject(typ, shape, path)
+	case model.KindArray:
+		switch underlying := typ.Underlying().(type) {
+		case *types.Slice:
+			d.diff(underlying.Elem(), shape.Elem, path+"[]")
+		case *types.Array:
+			d.diff(underlying.Elem(), shape.Elem, path+"[]")
+		default:
+			d.reportTypeMismatch(typ, shape, path)
+		}
+	default:
+		d.diffPrimitive(typ, shape, path)
+	}
+}
+
+func (d *differ) diffObject(typ types.Type, shape *model.Type, path string) {
+	switch underlying := typ.Underlying().(type) {
+	case *types.Struct:
+		fields := jsonFields(underlying)
+		isMatched := make(map[string]bool)
+		for _, shapeField := range shape.Fields {
+			fieldPath := joinPath(path, shapeField.Key)
+			field := lookupField(fields, shapeField.Key)
+			switch {
+			case field == nil:
+				d.report(DifferenceMissingField, fieldPath, "no field for the key, whose values are %v", describeShape(shapeField.Type))
+				continue
+			case field.name != shapeField.Key:
+				d.report(DifferenceTagMismatch, fieldPath, "the key only matches the json tag %q of field %v case-insensitively", field.name, field.goName)
+			}
+			isMatched[field.name] = true
+			d.diff(field.typ, shapeField.Type, fieldPath)
+		}
+		for _, field := range fields {
+			if !isMatched[field.name] {
+				d.report(DifferenceExtraField, joinPath(path, field.name), "field %v isn't in any sample", field.goName)
+			}
+		}
+	case *types.Map:
+		for _, shapeField := range shape.Fields {
+			d.diff(underlying.Elem(), shapeField.Type, joinPath(path, shapeField.Key))
+		}
+	default:
+		d.reportTypeMismatch(typ, shape, path)
+	}
+}
+
+func (d *differ) diffPrimitive(typ types.Type, shape *model.Type, path string) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		d.reportTypeMismatch(typ, shape, path)
+		return
+	}
+	info := basic.Info()
+	switch shape.Kind {
+	case model.KindString:
+		ok = info&types.IsString != 0
+	case model.KindBool:
+		ok = info&types.IsBoolean != 0
+	case model.KindInteger:
+		ok = info&types.IsNumeric != 0
+	case model.KindFloat:
+		ok = info&types.IsFloat != 0
+	}
+	if !ok {
+		d.reportTypeMismatch(typ, shape, path)
+		return
+	}
+	if shape.Kind == model.KindInteger {
+		for _, literal := range shape.Values {
+			if msg := checkNumber(basic, literal); msg != "" {
+				d.report(DifferenceTypeMismatch, path, "%v", msg)
+			}
+		}
+	}
+}
+
+func (d *differ) reportTypeMismatch(typ types.Type, shape *model.Type, path string) {
+	d.report(DifferenceTypeMismatch, path, "the samples have %v values, but the field is %v", describeShape(shape), typ)
+}
+
+// describeShape describes shape in terms of JSON, e.g. `string or null`.
+func describeShape(shape *model.Type) string {
+	switch shape.Kind {
+	case model.KindUnion:
+		description := ""
+		for i, variant := range shape.Variants {
+			if i > 0 {
+				description += " or "
+			}
+			description += describeShape(variant)
+		}
+		return description
+	default:
+		return shape.Kind.String()
+	}
+}
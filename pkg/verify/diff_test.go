@@ -0,0 +1,53 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/model"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+func TestDiff(t *testing.T) {
+	typ, err := LoadType("./testdata/api.User", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample, err := parse.ParseFromStringWithOptions(`{
+		"id": 1.5,
+		"name": "Ada",
+		"age": 300,
+		"created_at": "2024-03-01T12:30:00Z",
+		"address": { "city": "London", "zip": "N1" },
+		"tags": [1],
+		"nickname": null
+	}`, parse.Options{KeepValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, difference := range Diff(typ, model.Infer(sample)) {
+		got = append(got, difference.String())
+	}
+	want := []string{
+		"type mismatch: id: the samples have float values, but the field is int",
+		`tag mismatch: name: the key only matches the json tag "Name" of field Name case-insensitively`,
+		"type mismatch: age: cannot unmarshal number 300 into int8",
+		`missing field: address.zip: no field for the key, whose values are string`,
+		"type mismatch: tags[]: the samples have integer values, but the field is string",
+		"missing field: nickname: no field for the key, whose values are null",
+		"extra field: legacy_id: field LegacyID isn't in any sample",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff(): got\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadTypeInvalid(t *testing.T) {
+	for _, name := range []string{"User", "./testdata/api.", "./testdata/api.Missing", "./testdata/missing.User"} {
+		if _, err := LoadType(name, ""); err == nil {
+			t.Errorf("LoadType(%q): expected an error", name)
+		}
+	}
+}
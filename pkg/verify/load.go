@@ -0,0 +1,41 @@
+package verify
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadType loads the type named by qualifiedName, a package pattern and the name of the type separated by a
+// dot, e.g. `./api.User` or `github.com/example/api.User`. Relative patterns are resolved against dir, or the
+// working directory if dir is empty.
+func LoadType(qualifiedName, dir string) (types.Type, error) {
+	i := strings.LastIndex(qualifiedName, ".")
+	if i <= 0 || i == len(qualifiedName)-1 || strings.HasSuffix(qualifiedName[:i], "/") {
+		return nil, fmt.Errorf("invalid type %q. expected the package and the name of the type, e.g. ./api.User", qualifiedName)
+	}
+	pattern, typeName := qualifiedName[:i], qualifiedName[i+1:]
+
+	// Type checking the package and its dependencies from source is slower than reading export data, but doesn't
+	// depend on the export data format of the installed toolchain.
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps
+	cfg := &packages.Config{Mode: mode, Dir: dir}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error loading package %v: %v", pattern, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected %v to match a single package, but it matches %d", pattern, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("error loading package %v: %v", pattern, pkg.Errors[0])
+	}
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("package %v doesn't declare the type %v", pkg.PkgPath, typeName)
+	}
+	return obj.Type(), nil
+}
@@ -0,0 +1,18 @@
+// Package api is loaded by the tests of LoadType and Diff.
+package api
+
+import "time"
+
+type User struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"Name"`
+	Age       int8      `json:"age"`
+	CreatedAt time.Time `json:"created_at"`
+	LegacyID  string    `json:"legacy_id"`
+	Address   *Address  `json:"address"`
+	Tags      []string  `json:"tags"`
+}
+
+type Address struct {
+	City string `json:"city"`
+}
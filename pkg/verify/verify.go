@@ -0,0 +1,348 @@
+// Package verify checks whether samples fit Go types the way encoding/json would decode them. The types are
+// type checked with go/types instead of being compiled, and the samples are compared to their structure, so no
+// code has to be run.
+package verify
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/marhaupe/json2struct/pkg/internal/jsontag"
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+// Problem is a value of a sample that encoding/json couldn't decode into the type, or would drop.
+type Problem struct {
+	// Sample is the index of the sample the value belongs to.
+	Sample int
+	// Path locates the value within the sample, e.g. `users[2].age`.
+	Path string
+	// Msg describes the problem, e.g. `cannot unmarshal number 1.5 into int`.
+	Msg string
+}
+
+func (p Problem) String() string {
+	path := p.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%v: %v", path, p.Msg)
+}
+
+// TypeCheck type checks code, a single Go file, and returns the type with the given name.
+func TypeCheck(code, typeName string) (types.Type, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", code, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the generated code: %v", err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error type checking the generated code: %v", err)
+	}
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("the generated code doesn't declare the type %v", typeName)
+	}
+	return obj.Type(), nil
+}
+
+// Samples checks samples against typ and returns the problems found, in the order of the samples. The samples
+// have to be parsed with parse.Options.KeepValues, otherwise numbers can't be checked.
+func Samples(typ types.Type, samples []parse.Node) []Problem {
+	var problems []Problem
+	for i, sample := range samples {
+		c := &checker{sample: i}
+		c.check(typ, sample, "")
+		problems = append(problems, c.problems...)
+	}
+	return problems
+}
+
+// checker holds the state of checking a single sample.
+type checker struct {
+	sample   int
+	problems []Problem
+}
+
+func (c *checker) report(path, format string, args ...any) {
+	c.problems = append(c.problems, Problem{Sample: c.sample, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// check checks node, found at path, against typ.
+func (c *checker) check(typ types.Type, node parse.Node, path string) {
+	if node.Type() == parse.NodeTypeNil {
+		// encoding/json accepts null for any type.
+		return
+	}
+	if implementsUnmarshaler(typ) {
+		// Types decoding themselves can accept anything.
+		return
+	}
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		c.check(pointer.Elem(), node, path)
+		return
+	}
+	if iface, ok := typ.Underlying().(*types.Interface); ok {
+		if !iface.Empty() {
+			c.report(path, "cannot unmarshal %v into %v", describeNode(node), typ)
+		}
+		return
+	}
+
+	switch node := node.(type) {
+	case *parse.ObjectNode:
+		c.checkObject(typ, node, path)
+	case *parse.ArrayNode:
+		c.checkArray(typ, node, path)
+	case *parse.PrimitiveNode:
+		c.checkPrimitive(typ, node, path)
+	}
+}
+
+func (c *checker) checkObject(typ types.Type, object *parse.ObjectNode, path string) {
+	switch underlying := typ.Underlying().(type) {
+	case *types.Struct:
+		fields := jsonFields(underlying)
+		for _, key := range object.OrderedKeys() {
+			field := lookupField(fields, key)
+			if field == nil {
+				c.report(joinPath(path, key), "unknown field %q", key)
+				continue
+			}
+			for _, child := range object.Children[key] {
+				c.check(field.typ, child, joinPath(path, key))
+			}
+		}
+	case *types.Map:
+		if !isValidMapKey(underlying.Key()) {
+			c.report(path, "cannot unmarshal object into %v", typ)
+			return
+		}
+		for _, key := range object.OrderedKeys() {
+			for _, child := range object.Children[key] {
+				c.check(underlying.Elem(), child, joinPath(path, key))
+			}
+		}
+	default:
+		c.report(path, "cannot unmarshal object into %v", typ)
+	}
+}
+
+func (c *checker) checkArray(typ types.Type, array *parse.ArrayNode, path string) {
+	var elem types.Type
+	switch underlying := typ.Underlying().(type) {
+	case *types.Slice:
+		elem = underlying.Elem()
+	case *types.Array:
+		// Surplus elements are dropped silently.
+		if int64(len(array.Children)) > underlying.Len() {
+			c.report(path, "%d elements don't fit into %v", len(array.Children), typ)
+		}
+		elem = underlying.Elem()
+	default:
+		c.report(path, "cannot unmarshal array into %v", typ)
+		return
+	}
+	for i, child := range array.Children {
+		c.check(elem, child, fmt.Sprintf("%v[%d]", path, i))
+	}
+}
+
+func (c *checker) checkPrimitive(typ types.Type, node *parse.PrimitiveNode, path string) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		c.report(path, "cannot unmarshal %v into %v", describeNode(node), typ)
+		return
+	}
+	info := basic.Info()
+	switch node.Type() {
+	case parse.NodeTypeString:
+		if info&types.IsString == 0 {
+			c.report(path, "cannot unmarshal string into %v", typ)
+		}
+	case parse.NodeTypeBool:
+		if info&types.IsBoolean == 0 {
+			c.report(path, "cannot unmarshal bool into %v", typ)
+		}
+	case parse.NodeTypeInteger, parse.NodeTypeFloat:
+		if info&types.IsNumeric == 0 {
+			c.report(path, "cannot unmarshal number into %v", typ)
+			return
+		}
+		if msg := checkNumber(basic, node.Literal); msg != "" {
+			c.report(path, "%v", msg)
+		}
+	}
+}
+
+// checkNumber returns why the number literal doesn't fit basic, or an empty string if it does or the literal
+// wasn't kept.
+func checkNumber(basic *types.Basic, literal string) string {
+	if literal == "" {
+		return ""
+	}
+	info := basic.Info()
+	var err error
+	switch {
+	case info&types.IsInteger != 0 && info&types.IsUnsigned != 0:
+		_, err = strconv.ParseUint(literal, 10, sizeOf(basic))
+	case info&types.IsInteger != 0:
+		_, err = strconv.ParseInt(literal, 10, sizeOf(basic))
+	case info&types.IsFloat != 0:
+		var number float64
+		number, err = strconv.ParseFloat(literal, sizeOf(basic))
+		if err == nil && (math.IsInf(number, 0) || math.IsNaN(number)) {
+			err = strconv.ErrRange
+		}
+	default:
+		return fmt.Sprintf("cannot unmarshal number %v into %v", literal, basic)
+	}
+	if err != nil {
+		return fmt.Sprintf("cannot unmarshal number %v into %v", literal, basic)
+	}
+	return ""
+}
+
+// sizeOf returns the size of basic in bits. int and uint are assumed to be 64 bits wide.
+func sizeOf(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// jsonField is a field of a struct as encoding/json sees it.
+type jsonField struct {
+	name   string
+	goName string
+	typ    types.Type
+}
+
+// jsonFields returns the fields encoding/json decodes into, named by their json tags. Like encoding/json, it
+// ignores unexported fields and fields tagged with "-", and promotes the fields of embedded structs.
+func jsonFields(s *types.Struct) []jsonField {
+	var fields []jsonField
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		if value, _ := reflect.StructTag(s.Tag(i)).Lookup("json"); value == "-" {
+			continue
+		}
+		name, hasName := jsonName(s.Tag(i))
+		if field.Embedded() && !hasName {
+			embedded := field.Type()
+			if pointer, ok := embedded.Underlying().(*types.Pointer); ok {
+				embedded = pointer.Elem()
+			}
+			if embeddedStruct, ok := embedded.Underlying().(*types.Struct); ok {
+				fields = append(fields, jsonFields(embeddedStruct)...)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if !hasName {
+			name = field.Name()
+		}
+		fields = append(fields, jsonField{name: name, goName: field.Name(), typ: field.Type()})
+	}
+	return fields
+}
+
+// jsonName returns the name of the json tag of a field, and whether it has a valid one.
+func jsonName(tag string) (string, bool) {
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		return "", false
+	}
+	name, _, _ := strings.Cut(value, ",")
+	if !jsontag.IsValidName(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// lookupField returns the field matching key. Like encoding/json, it prefers an exact match over a case
+// insensitive one.
+func lookupField(fields []jsonField, key string) *jsonField {
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, key) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func isValidMapKey(key types.Type) bool {
+	if implementsTextUnmarshaler(key) {
+		return true
+	}
+	basic, ok := key.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsString|types.IsInteger) != 0
+}
+
+func implementsUnmarshaler(typ types.Type) bool {
+	return hasMethod(typ, "UnmarshalJSON") || hasMethod(typ, "UnmarshalText")
+}
+
+func implementsTextUnmarshaler(typ types.Type) bool {
+	return hasMethod(typ, "UnmarshalText")
+}
+
+// hasMethod reports whether typ or a pointer to it has the method. encoding/json decodes into addressable
+// values, so methods with pointer receivers count.
+func hasMethod(typ types.Type, name string) bool {
+	if _, isPointer := typ.(*types.Pointer); !isPointer {
+		typ = types.NewPointer(typ)
+	}
+	methods := types.NewMethodSet(typ)
+	for i := 0; i < methods.Len(); i++ {
+		if methods.At(i).Obj().Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func describeNode(node parse.Node) string {
+	switch node.Type() {
+	case parse.NodeTypeObject:
+		return "object"
+	case parse.NodeTypeArray:
+		return "array"
+	case parse.NodeTypeString:
+		return "string"
+	case parse.NodeTypeBool:
+		return "bool"
+	default:
+		return "number"
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
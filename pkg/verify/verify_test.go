@@ -0,0 +1,111 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marhaupe/json2struct/pkg/parse"
+)
+
+const code = `package generated
+
+import "time"
+
+type Embedded struct {
+	Source string ` + "`json:\"source\"`" + `
+}
+
+type User struct {
+	Embedded
+	ID       int               ` + "`json:\"id\"`" + `
+	Small    int8              ` + "`json:\"small\"`" + `
+	Score    float64           ` + "`json:\"score\"`" + `
+	Name     string            ` + "`json:\"name\"`" + `
+	Admin    bool              ` + "`json:\"admin\"`" + `
+	Tags     []string          ` + "`json:\"tags\"`" + `
+	Pair     [2]int            ` + "`json:\"pair\"`" + `
+	Labels   map[string]string ` + "`json:\"labels\"`" + `
+	Created  time.Time         ` + "`json:\"created\"`" + `
+	Extra    interface{}       ` + "`json:\"extra\"`" + `
+	Ignored  string            ` + "`json:\"-\"`" + `
+	Parent   *User             ` + "`json:\"parent\"`" + `
+	_Schema  string            ` + "`json:\"$schema\"`" + `
+	Untagged string
+}
+`
+
+func TestSamples(t *testing.T) {
+	typ, err := TypeCheck(code, "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{
+			name: "fitting sample",
+			json: `{
+				"source": "api", "id": 1, "small": -128, "score": 1, "name": "a", "admin": true, "tags": ["x"],
+				"pair": [1, 2], "labels": { "a": "b" }, "created": "2024-03-01T12:30:00Z", "extra": [1, "a"],
+				"parent": { "id": 2, "parent": null }, "untagged": "case insensitive", "NAME": "b"
+			}`,
+		},
+		{
+			name: "numbers that don't fit",
+			json: `{ "id": 1.5, "small": 128, "score": 1e999, "parent": { "id": 10000000000000000999 } }`,
+			want: []string{
+				"id: cannot unmarshal number 1.5 into int",
+				"small: cannot unmarshal number 128 into int8",
+				"score: cannot unmarshal number 1e999 into float64",
+				"parent.id: cannot unmarshal number 10000000000000000999 into int",
+			},
+		},
+		{
+			name: "mismatched kinds",
+			json: `{ "name": 1, "admin": "yes", "tags": "x", "labels": [], "pair": [1, 2, 3], "id": null }`,
+			want: []string{
+				"name: cannot unmarshal number into string",
+				"admin: cannot unmarshal string into bool",
+				"tags: cannot unmarshal string into []string",
+				"labels: cannot unmarshal array into map[string]string",
+				"pair: 3 elements don't fit into [2]int",
+			},
+		},
+		{
+			name: "unknown fields",
+			json: `{ "$schema": "x", "Ignored": "y", "other": 1 }`,
+			want: []string{
+				`$schema: unknown field "$schema"`,
+				`Ignored: unknown field "Ignored"`,
+				`other: unknown field "other"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sample, err := parse.ParseFromStringWithOptions(test.json, parse.Options{KeepValues: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, problem := range Samples(typ, []parse.Node{sample}) {
+				got = append(got, problem.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("Samples(): got\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestTypeCheck(t *testing.T) {
+	if _, err := TypeCheck("package generated\n\ntype A struct { B Missing }\n", "A"); err == nil {
+		t.Errorf("TypeCheck(): expected an error for an undefined type")
+	}
+	if _, err := TypeCheck("package generated\n\ntype A struct{}\n", "B"); err == nil {
+		t.Errorf("TypeCheck(): expected an error for a missing type")
+	}
+}